@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/signal"
@@ -13,10 +14,14 @@ import (
 
 	"github.com/Shopify/sarama"
 	"github.com/acikkaynak/backend-api-go/broker"
+	"github.com/acikkaynak/backend-api-go/expiry"
+	"github.com/acikkaynak/backend-api-go/geocoding"
 	"github.com/acikkaynak/backend-api-go/handler"
+	"github.com/acikkaynak/backend-api-go/matching"
 	"github.com/acikkaynak/backend-api-go/middleware/auth"
 	"github.com/acikkaynak/backend-api-go/middleware/cache"
 	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/reader"
 	"github.com/acikkaynak/backend-api-go/repository"
 	_ "github.com/acikkaynak/backend-api-go/swagger"
 	swagger "github.com/arsmn/fiber-swagger/v2"
@@ -29,10 +34,11 @@ import (
 )
 
 type Application struct {
-	app           *fiber.App
-	repo          *repository.Repository
-	index         *search.LocationIndex
-	kafkaProducer sarama.SyncProducer
+	app            *fiber.App
+	repo           *repository.Repository
+	index          *search.LocationIndex
+	locationReader *reader.Router
+	kafkaProducer  sarama.SyncProducer
 }
 
 func (a *Application) Register() {
@@ -40,15 +46,47 @@ func (a *Application) Register() {
 	a.app.Get("/healthcheck", handler.HealthCheck)
 	a.app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 	a.app.Get("/monitor", monitor.New())
-	a.app.Get("/feeds/areas", handler.GetFeedAreas(a.repo, a.index))
+	a.app.Get("/feeds/areas", handler.GetFeedAreas(a.repo, a.locationReader))
+	a.app.Get("/feeds/clusters", handler.GetFeedClusters(a.locationReader))
+	a.app.Get("/feeds/heatmap", handler.GetFeedHeatmap(a.repo))
+	a.app.Get("/feeds/changes", handler.GetFeedChanges(a.repo))
+	a.app.Get("/feeds/search", handler.GetFeedSearch(a.locationReader))
+	a.app.Get("/feeds/export", handler.GetFeedExport(a.repo))
 	a.app.Patch("/feeds/areas", handler.UpdateFeedLocationsHandler(a.repo))
 	a.app.Get("/feeds/:id/", handler.GetFeedById(a.repo))
+	a.app.Post("/feeds/:id/resolve", handler.ResolveFeedHandler(a.repo, a.index))
+	a.app.Post("/feeds/:id/reopen", handler.ReopenFeedHandler(a.repo, a.index))
+	a.app.Get("/feeds/:id/history", handler.GetLocationHistoryHandler(a.repo))
+	a.app.Post("/feeds/:id/history/:correctionId/rollback", handler.RollbackLocationHandler(a.repo))
+	a.app.Get("/feeds/:id/needs/history", handler.GetNeedHistoryHandler(a.repo))
+	a.app.Patch("/feeds/:id/needs/:label", handler.UpdateNeedStatusHandler(a.repo, a.index))
+	a.app.Get("/tiles/:z/:x/:y.mvt", handler.GetTile(a.repo))
+	a.app.Get("/admin/feeds/deleted", handler.GetDeletedFeedsHandler(a.repo))
+	a.app.Post("/admin/feeds/:id/restore", handler.RestoreFeedHandler(a.repo, a.index))
 	a.app.Post("/events", handler.CreateEventHandler(a.kafkaProducer))
 	a.app.Get("/caches/prune", handler.InvalidateCache())
 	a.app.Get("/reasons", handler.GetReasonsHandler(a.repo))
-	needsHandler := handler.NewNeedsHandler(a.repo)
+	matcher := matching.NewService(a.repo)
+	needsHandler := handler.NewNeedsHandler(a.repo, matcher)
 	a.app.Get("/needs", needsHandler.HandleList)
 	a.app.Post("/needs", needsHandler.HandleCreate)
+	a.app.Get("/needs/:id", needsHandler.HandleGet)
+	a.app.Patch("/needs/:id", needsHandler.HandleUpdate)
+	a.app.Delete("/needs/:id", needsHandler.HandleDelete)
+	a.app.Post("/needs/:id/resolve", needsHandler.HandleResolve)
+	a.app.Post("/needs/:id/merge", needsHandler.HandleMerge)
+	a.app.Get("/needs/:id/history", needsHandler.HandleHistory)
+	a.app.Get("/needs/:id/matches", needsHandler.HandleMatches)
+	offersHandler := handler.NewOffersHandler(a.repo, matcher)
+	a.app.Get("/offers", offersHandler.HandleList)
+	a.app.Post("/offers", offersHandler.HandleCreate)
+	a.app.Get("/offers/:id", offersHandler.HandleGet)
+	a.app.Patch("/offers/:id", offersHandler.HandleUpdate)
+	a.app.Get("/offers/:id/matches", offersHandler.HandleMatches)
+	reviewHandler := handler.NewReviewHandler(a.repo, a.index)
+	a.app.Post("/reviews/claim", reviewHandler.HandleClaim)
+	a.app.Post("/reviews/:id", reviewHandler.HandleSubmit)
+	a.app.Delete("/reviews/:id/claim", reviewHandler.HandleRelease)
 	route := a.app.Group("/swagger")
 	route.Get("*", swagger.HandlerDefault)
 }
@@ -93,9 +131,33 @@ func main() {
 	app.Use(pprof.New())
 	app.Use(cache.New())
 
-	application := &Application{app: app, repo: repo, index: index, kafkaProducer: kafkaProducer}
+	application := &Application{
+		app:            app,
+		repo:           repo,
+		index:          index,
+		locationReader: reader.NewRouter(repo, index),
+		kafkaProducer:  kafkaProducer,
+	}
 	application.Register()
 
+	jobCtx, cancelJobs := context.WithCancel(context.Background())
+	defer cancelJobs()
+	go index.RunTurkishSearchSetup(jobCtx)
+	go expiry.NewJob(repo, index).Run(jobCtx)
+	go func() {
+		filled, err := repo.BackfillNormalizedAddresses(jobCtx, 1000)
+		if err != nil {
+			log.Logger().Error("could not backfill normalized need addresses. err: " + err.Error())
+			return
+		}
+		if filled > 0 {
+			log.Logger().Info(fmt.Sprintf("backfilled normalized addresses of %d needs", filled))
+		}
+	}()
+	if geocoder := geocoding.NewFromEnv(); geocoder != nil {
+		go geocoding.NewWorker(repo, geocoder).Run(jobCtx)
+	}
+
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, syscall.SIGINT)
 	signal.Notify(c, syscall.SIGTERM)
@@ -103,6 +165,7 @@ func main() {
 	go func() {
 		_ = <-c
 		log.Logger().Info("application gracefully shutting down..")
+		cancelJobs()
 		_ = app.Shutdown()
 	}()
 
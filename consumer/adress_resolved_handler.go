@@ -24,9 +24,19 @@ type FeedMessage struct {
 	Reason           *string   `json:"reason,omitempty"`
 }
 
+// ConsumeMessagePayload carries either a single location or, for messages listing several places, locations.
 type ConsumeMessagePayload struct {
-	Location feeds.Location `json:"location"`
-	Feed     FeedMessage    `json:"feed"`
+	Location  feeds.Location   `json:"location"`
+	Locations []feeds.Location `json:"locations,omitempty"`
+	Feed      FeedMessage      `json:"feed"`
+}
+
+// AllLocations returns the locations of the message, falling back to the single location.
+func (p ConsumeMessagePayload) AllLocations() []feeds.Location {
+	if len(p.Locations) > 0 {
+		return p.Locations
+	}
+	return []feeds.Location{p.Location}
 }
 
 func (consumer *Consumer) addressResolveHandle(message *sarama.ConsumerMessage, session sarama.ConsumerGroupSession) {
@@ -55,17 +65,25 @@ func (consumer *Consumer) addressResolveHandle(message *sarama.ConsumerMessage,
 		Reason:           messagePayload.Feed.Reason,
 	}
 
-	err, entryID := consumer.repo.CreateFeed(ctx, f, messagePayload.Location)
+	entryID, locations, err := consumer.repo.CreateFeed(ctx, f, messagePayload.AllLocations())
 	if err != nil {
 		log.Logger().Error("error inserting feed entry and location", zap.String("payload", string(message.Value)), zap.Error(err))
 		return
 	}
 
+	if len(locations) == 0 {
+		session.MarkMessage(message, "")
+		session.Commit()
+		return
+	}
+
+	// the elastic documents have to carry the ids of the stored location rows
 	intentPayloadByte, err := jsoniter.Marshal(IntentMessagePayload{
 		FeedID:          entryID,
 		FullText:        messagePayload.Feed.FullText,
-		ResolvedAddress: messagePayload.Location.FormattedAddress,
-		Location:        messagePayload.Location,
+		ResolvedAddress: locations[0].FormattedAddress,
+		Location:        locations[0],
+		Locations:       locations,
 	})
 
 	_, _, err = consumer.producer.SendMessage(&sarama.ProducerMessage{
@@ -0,0 +1,42 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsumeMessagePayloadAllLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []feeds.Location
+	}{
+		{
+			name:    "single location message",
+			payload: `{"location": {"formatted_address": "Antakya", "latitude": 36.2}, "feed": {"raw_text": "yardım"}}`,
+			want:    []feeds.Location{{FormattedAddress: "Antakya", Latitude: 36.2}},
+		},
+		{
+			name: "several locations",
+			payload: `{"location": {"formatted_address": "Antakya"}, "locations": [{"formatted_address": "Antakya"},
+				{"formatted_address": "İskenderun"}], "feed": {"raw_text": "yardım"}}`,
+			want: []feeds.Location{{FormattedAddress: "Antakya"}, {FormattedAddress: "İskenderun"}},
+		},
+		{
+			name:    "empty locations fall back to the location",
+			payload: `{"location": {"formatted_address": "Kahramanmaraş"}, "locations": [], "feed": {}}`,
+			want:    []feeds.Location{{FormattedAddress: "Kahramanmaraş"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload ConsumeMessagePayload
+			assert.NoError(t, jsoniter.Unmarshal([]byte(tt.payload), &payload))
+			assert.Equal(t, tt.want, payload.AllLocations())
+		})
+	}
+}
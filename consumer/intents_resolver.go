@@ -3,7 +3,7 @@ package consumer
 import (
 	"bytes"
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"os"
 	"strings"
@@ -12,12 +12,15 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/acikkaynak/backend-api-go/feeds"
 	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
 	jsoniter "github.com/json-iterator/go"
 	"go.uber.org/zap"
 )
 
 var (
 	duplicationApiUrlDefault = "https://deduplication-api.afetharita.com/is-duplicate"
+
+	errIrrelevant = errors.New("alakasiz veri")
 )
 
 type IntentRequest struct {
@@ -33,11 +36,31 @@ type Intent []struct {
 	Score float64 `json:"score"`
 }
 
+// IntentMessagePayload carries the stored locations of an entry, Location is the first of them
+// and is kept for messages produced before entries had several locations.
 type IntentMessagePayload struct {
-	FeedID          int64          `json:"id"`
-	FullText        string         `json:"full_text"`
-	ResolvedAddress string         `json:"resolved_address"`
-	Location        feeds.Location `json:"location"`
+	FeedID          int64            `json:"id"`
+	FullText        string           `json:"full_text"`
+	ResolvedAddress string           `json:"resolved_address"`
+	Location        feeds.Location   `json:"location"`
+	Locations       []feeds.Location `json:"locations,omitempty"`
+}
+
+// AllLocations returns the locations of the message. Messages with a single location may predate
+// the location ids being sent, their only location shares its id with the entry.
+func (p IntentMessagePayload) AllLocations() []feeds.Location {
+	if len(p.Locations) > 0 {
+		return p.Locations
+	}
+
+	location := p.Location
+	if location.ID == 0 {
+		location.ID = p.FeedID
+	}
+	if location.EntryID == 0 {
+		location.EntryID = p.FeedID
+	}
+	return []feeds.Location{location}
 }
 
 type DuplicationRequest struct {
@@ -62,10 +85,10 @@ func (consumer *Consumer) intentResolveHandle(message *sarama.ConsumerMessage, s
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	intents, err := sendIntentResolveRequest(messagePayload.FullText, messagePayload.FeedID)
+	intents, scores, err := sendIntentResolveRequest(messagePayload.FullText, messagePayload.FeedID)
 	if err != nil {
-		if err.Error() == "alakasiz veri" {
-			if err := consumer.repo.DeleteFeedLocation(ctx, messagePayload.FeedID); err != nil {
+		if errors.Is(err, errIrrelevant) {
+			if err := consumer.repo.DeleteFeedLocation(ctx, messagePayload.FeedID, feeds.DeletionReasonIrrelevant, scores); err != nil {
 				log.Logger().Error("", zap.Error(err))
 			}
 		}
@@ -82,13 +105,42 @@ func (consumer *Consumer) intentResolveHandle(message *sarama.ConsumerMessage, s
 		return
 	}
 
+	for _, location := range messagePayload.AllLocations() {
+		if err := consumer.resolveLocation(ctx, messagePayload, location, intents, needs, scores); err != nil {
+			// the message is not committed so it is delivered again, resolving a location twice is harmless
+			log.Logger().Error("error updating feed entry, location intent and needs",
+				zap.Error(err), zap.Int64("location_id", location.ID), zap.String("payload", string(message.Value)))
+			return
+		}
+	}
+
+	session.MarkMessage(message, "")
+	session.Commit()
+}
+
+// resolveLocation stores the intents and needs of one location of the entry and indexes it, a location
+// that already carries its own reason or needs keeps them. Duplicate locations are deleted on their own,
+// the other locations of the entry are kept. Only storing the intents and needs fails it, the other steps are logged.
+func (consumer *Consumer) resolveLocation(ctx context.Context, messagePayload IntentMessagePayload, location feeds.Location, intents string, needs []feeds.NeedItem, scores map[string]float64) error {
+	if location.Reason != nil && *location.Reason != "" {
+		intents = *location.Reason
+	}
+	if len(location.Needs) > 0 {
+		needs = location.Needs
+	}
+
+	address := location.FormattedAddress
+	if address == "" {
+		address = messagePayload.ResolvedAddress
+	}
+
 	needsForDuplication := make([]string, 0)
 	for _, n := range needs {
 		needsForDuplication = append(needsForDuplication, n.Label)
 	}
 	isDuplicate, err := checkDuplication(DuplicationRequest{
 		EntryID: messagePayload.FeedID,
-		Address: messagePayload.ResolvedAddress,
+		Address: address,
 		Intents: strings.Split(intents, ","),
 		Needs:   needsForDuplication,
 	})
@@ -99,36 +151,60 @@ func (consumer *Consumer) intentResolveHandle(message *sarama.ConsumerMessage, s
 	}
 
 	if isDuplicate {
-		if err := consumer.repo.DeleteFeedLocation(ctx, messagePayload.FeedID); err != nil {
+		clusterID, reportCount, err := consumer.repo.AddDuplicate(ctx, location.ID)
+		if err == nil {
+			if err := consumer.index.SetReportCount(ctx, clusterID, reportCount); err != nil {
+				log.Logger().Error("error updating elastic cluster report count",
+					zap.Int64("cluster_id", clusterID), zap.Error(err))
+			}
+			return nil
+		}
+		if !errors.Is(err, repository.ErrCanonicalNotFound) {
+			log.Logger().Error("could not add location to duplicate cluster",
+				zap.Int64("location_id", location.ID), zap.Error(err))
+		}
+
+		// a duplicate which can not be linked to a canonical location is not listed
+		if err := consumer.repo.DeleteLocation(ctx, location.ID, feeds.DeletionReasonDuplicate, scores); err != nil {
 			log.Logger().Error("could not delete feed location after duplication request",
 				zap.Int64("entry_id", messagePayload.FeedID),
+				zap.Int64("location_id", location.ID),
 				zap.Error(err))
 		}
-		session.MarkMessage(message, "")
-		session.Commit()
-		return
+		return nil
 	}
 
-	if err := consumer.repo.UpdateLocationIntentAndNeeds(ctx, messagePayload.FeedID, intents, needs); err != nil {
-		log.Logger().Error("error updating feed entry, location intent and needs",
-			zap.Error(err), zap.String("payload", string(message.Value)))
-		return
+	if err := consumer.repo.UpdateLocationIntentAndNeeds(ctx, location.ID, intents, needs); err != nil {
+		return err
 	}
 
-	messagePayload.Location.Needs = needs
-	messagePayload.Location.Reason = &intents
+	// a location reported again is current again, even when it was not recognised as a duplicate
+	refreshedIDs, err := consumer.repo.RefreshStale(ctx, location.ID)
+	if err != nil {
+		log.Logger().Error("could not refresh stale locations",
+			zap.Int64("location_id", location.ID), zap.Error(err))
+	} else if len(refreshedIDs) > 0 {
+		if err := consumer.index.SetCurrent(ctx, refreshedIDs); err != nil {
+			log.Logger().Error("error updating elastic refreshed locations",
+				zap.Int64s("location_ids", refreshedIDs), zap.Error(err))
+		}
+	}
+
+	location.Needs = needs
+	location.Reason = &intents
 
-	if err := consumer.index.CreateFeedLocation(ctx, messagePayload.FullText, messagePayload.Location); err != nil {
+	if err := consumer.index.CreateFeedLocation(ctx, messagePayload.FullText, location); err != nil {
 		//TODO commit message when we enable elastic reads
 		log.Logger().Error("error updating elastic location intent and needs",
-			zap.Any("intentAndNeeds", messagePayload), zap.Error(err), zap.String("rawMessage", string(message.Value)))
+			zap.Any("location", location), zap.Error(err))
 	}
 
-	session.MarkMessage(message, "")
-	session.Commit()
+	return nil
 }
 
-func sendIntentResolveRequest(fullText string, feedID int64) (string, error) {
+// sendIntentResolveRequest returns the intents scored at least 0.4 and the scores of every label.
+// Texts labeled Alakasiz with a score of at least 0.7 return errIrrelevant together with the scores.
+func sendIntentResolveRequest(fullText string, feedID int64) (string, map[string]float64, error) {
 	jsonBytes, err := jsoniter.Marshal(IntentRequest{
 		Inputs: fullText,
 	})
@@ -136,39 +212,50 @@ func sendIntentResolveRequest(fullText string, feedID int64) (string, error) {
 	req, err := http.NewRequest("POST", os.Getenv("INTENT_RESOLVER_API_URL"), bytes.NewReader(jsonBytes))
 	if err != nil {
 		log.Logger().Error("could not prepare http request IntentMessagePayload", zap.String("fullText", fullText), zap.Error(err))
-		return "", err
+		return "", nil, err
 	}
 	req.Header.Add("Authorization", "Bearer "+os.Getenv("INTENT_RESOLVER_API_KEY"))
 	req.Header.Add("content-type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Logger().Error("could not send request IntentMessagePayload", zap.Int64("feedID", feedID), zap.Error(err))
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		log.Logger().Error("could not get response IntentMessagePayload", zap.Int64("feedID", feedID), zap.Int("statusCode", resp.StatusCode))
-		return "", err
+		return "", nil, err
 	}
 
 	intentResp := &IntentResponse{}
 	if err := jsoniter.NewDecoder(resp.Body).Decode(&intentResp.Results); err != nil {
 		log.Logger().Error("could not get decode response IntentMessagePayload", zap.Int64("feedID", feedID), zap.Error(err))
-		return "", err
+		return "", nil, err
 	}
 
 	if len(intentResp.Results) == 0 {
 		log.Logger().Error("no data found on response IntentMessagePayload", zap.Int64("feedID", feedID))
-		return "", nil
+		return "", nil, nil
+	}
+
+	scores := make(map[string]float64, len(intentResp.Results[0]))
+	for _, val := range intentResp.Results[0] {
+		scores[val.Label] = val.Score
 	}
 
 	intents := make([]string, 0)
 	for _, val := range intentResp.Results[0] {
 		if val.Score >= 0.4 {
 			if val.Label == "Alakasiz" && val.Score >= 0.7 {
-				return "", fmt.Errorf("alakasiz veri")
+				return "", scores, errIrrelevant
 			}
 			intents = append(intents, strings.ToLower(val.Label))
 		}
 	}
 
-	return strings.Join(intents, ","), nil
+	return strings.Join(intents, ","), scores, nil
 }
 
 func checkDuplication(payload DuplicationRequest) (bool, error) {
@@ -188,6 +275,12 @@ func checkDuplication(payload DuplicationRequest) (bool, error) {
 	req.Header.Add("content-type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Logger().Error("could not send request DuplicationRequest", zap.Error(err))
+		return false, err
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		log.Logger().Error("could not get response DuplicationRequest", zap.Error(err))
 		return false, err
@@ -0,0 +1,42 @@
+package consumer
+
+import (
+	"testing"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntentMessagePayloadAllLocations(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    []feeds.Location
+	}{
+		{
+			name:    "legacy message without location ids",
+			payload: `{"id": 7, "full_text": "yardım", "location": {"formatted_address": "Antakya"}}`,
+			want:    []feeds.Location{{ID: 7, EntryID: 7, FormattedAddress: "Antakya"}},
+		},
+		{
+			name:    "single location with ids",
+			payload: `{"id": 7, "location": {"id": 7, "entry_id": 7, "formatted_address": "Antakya"}}`,
+			want:    []feeds.Location{{ID: 7, EntryID: 7, FormattedAddress: "Antakya"}},
+		},
+		{
+			name: "several locations",
+			payload: `{"id": 7, "location": {"id": 7, "entry_id": 7}, "locations": [{"id": 7, "entry_id": 7},
+				{"id": 12, "entry_id": 7, "formatted_address": "İskenderun"}]}`,
+			want: []feeds.Location{{ID: 7, EntryID: 7}, {ID: 12, EntryID: 7, FormattedAddress: "İskenderun"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload IntentMessagePayload
+			assert.NoError(t, jsoniter.Unmarshal([]byte(tt.payload), &payload))
+			assert.Equal(t, tt.want, payload.AllLocations())
+		})
+	}
+}
@@ -41,6 +41,12 @@ func sendNeedsResolveRequest(fullText string, feedID int64) ([]feeds.NeedItem, e
 	req.Header.Add("content-type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		log.Logger().Error("could not send request NeedsMessagePayload", zap.Int64("feedID", feedID), zap.Error(err))
+		return nil, err
+	}
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
 		log.Logger().Error("could not get response NeedsMessagePayload", zap.Int64("feedID", feedID), zap.Int("statusCode", resp.StatusCode))
 		return nil, err
@@ -0,0 +1,105 @@
+// Package expiry runs the background job flagging feed locations which expired under the staleness policy.
+package expiry
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval = 10 * time.Minute
+	syncBatchSize   = 1000
+)
+
+type Job struct {
+	repo     *repository.Repository
+	index    *search.LocationIndex
+	policy   feeds.StalenessPolicy
+	interval time.Duration
+}
+
+// NewJob reads STALENESS_POLICY ("reason:duration" pairs on top of feeds.DefaultStalenessPolicy)
+// and STALENESS_JOB_INTERVAL (a duration, 10m by default).
+func NewJob(repo *repository.Repository, index *search.LocationIndex) *Job {
+	policy, err := feeds.ParseStalenessPolicy(os.Getenv("STALENESS_POLICY"), feeds.DefaultStalenessPolicy)
+	if err != nil {
+		log.Logger().Error("invalid STALENESS_POLICY, using defaults", zap.Error(err))
+		policy = feeds.DefaultStalenessPolicy
+	}
+
+	interval := defaultInterval
+	if i, err := time.ParseDuration(os.Getenv("STALENESS_JOB_INTERVAL")); err == nil && i > 0 {
+		interval = i
+	}
+
+	return &Job{
+		repo:     repo,
+		index:    index,
+		policy:   policy,
+		interval: interval,
+	}
+}
+
+// Run marks stale locations every interval until ctx is done.
+func (j *Job) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.RunOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce marks the locations which expired since the last run as stale in postgres and syncs every stale
+// location elastic has not seen yet, so locations of a failed sync are retried on the next run.
+// Elastic failures are only logged, stale_synced_at stays empty until a sync succeeds.
+func (j *Job) RunOnce(ctx context.Context) {
+	ids, err := j.repo.MarkStale(ctx, j.policy)
+	if err != nil {
+		log.Logger().Error("could not mark stale locations", zap.Error(err))
+		return
+	}
+
+	if len(ids) > 0 {
+		log.Logger().Info("marked locations stale", zap.Int("count", len(ids)))
+	}
+
+	for {
+		ids, err := j.repo.GetUnsyncedStale(ctx, syncBatchSize)
+		if err != nil {
+			log.Logger().Error("could not get unsynced stale locations", zap.Error(err))
+			return
+		}
+
+		if len(ids) == 0 {
+			return
+		}
+
+		if err := j.index.SetStale(ctx, ids); err != nil {
+			log.Logger().Error("error updating elastic stale locations", zap.Int("count", len(ids)), zap.Error(err))
+			return
+		}
+
+		if err := j.repo.SetStaleSynced(ctx, ids); err != nil {
+			log.Logger().Error("could not record stale sync", zap.Int("count", len(ids)), zap.Error(err))
+			return
+		}
+
+		if len(ids) < syncBatchSize {
+			return
+		}
+	}
+}
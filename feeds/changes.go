@@ -0,0 +1,96 @@
+package feeds
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidChangeToken = errors.New("invalid change token")
+
+// ChangesResponse lists the locations written after a change token.
+// Deleted locations are reported by id in Tombstones, everything else in Upserts.
+type ChangesResponse struct {
+	Upserts    []Location `json:"upserts"`
+	Tombstones []int64    `json:"tombstones"`
+	NextToken  string     `json:"next_token"`
+	HasMore    bool       `json:"has_more"`
+}
+
+// LocationChange is a feeds_location row read in change order, by the transaction which wrote it and
+// its change sequence within that transaction.
+type LocationChange struct {
+	Location  Location
+	IsDeleted bool
+	ChangeXID int64
+	ChangeSeq int64
+}
+
+// ChangeToken is the position of the last change a client has seen.
+type ChangeToken struct {
+	XID int64
+	Seq int64
+}
+
+func EncodeChangeToken(token ChangeToken) string {
+	raw := strconv.FormatInt(token.XID, 10) + ":" + strconv.FormatInt(token.Seq, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeChangeToken returns the change position of a token, an empty token starts from the beginning.
+func DecodeChangeToken(token string) (ChangeToken, error) {
+	if token == "" {
+		return ChangeToken{}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return ChangeToken{}, ErrInvalidChangeToken
+	}
+
+	rawXID, rawSeq, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return ChangeToken{}, ErrInvalidChangeToken
+	}
+
+	xid, err := strconv.ParseInt(rawXID, 10, 64)
+	if err != nil || xid < 0 {
+		return ChangeToken{}, ErrInvalidChangeToken
+	}
+
+	seq, err := strconv.ParseInt(rawSeq, 10, 64)
+	if err != nil || seq < 0 {
+		return ChangeToken{}, ErrInvalidChangeToken
+	}
+
+	return ChangeToken{XID: xid, Seq: seq}, nil
+}
+
+// NewChangesResponse splits changes into upserts and tombstones. Readers fetch one change more than limit,
+// a surplus change means the client should ask again with NextToken right away.
+func NewChangesResponse(changes []LocationChange, since ChangeToken, limit int) *ChangesResponse {
+	resp := &ChangesResponse{
+		Upserts:    make([]Location, 0),
+		Tombstones: make([]int64, 0),
+	}
+
+	if len(changes) > limit {
+		changes = changes[:limit]
+		resp.HasMore = true
+	}
+
+	last := since
+	for _, change := range changes {
+		if change.IsDeleted {
+			resp.Tombstones = append(resp.Tombstones, change.Location.ID)
+		} else {
+			resp.Upserts = append(resp.Upserts, change.Location)
+		}
+		last = ChangeToken{XID: change.ChangeXID, Seq: change.ChangeSeq}
+	}
+
+	resp.NextToken = EncodeChangeToken(last)
+
+	return resp
+}
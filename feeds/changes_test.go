@@ -0,0 +1,45 @@
+package feeds
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeToken(t *testing.T) {
+	token, err := DecodeChangeToken(EncodeChangeToken(ChangeToken{XID: 7, Seq: 42}))
+	assert.NoError(t, err)
+	assert.Equal(t, ChangeToken{XID: 7, Seq: 42}, token)
+
+	token, err = DecodeChangeToken("")
+	assert.NoError(t, err)
+	assert.Equal(t, ChangeToken{}, token)
+
+	_, err = DecodeChangeToken("%%")
+	assert.ErrorIs(t, err, ErrInvalidChangeToken)
+
+	_, err = DecodeChangeToken(base64.RawURLEncoding.EncodeToString([]byte("42")))
+	assert.ErrorIs(t, err, ErrInvalidChangeToken)
+}
+
+func TestNewChangesResponse(t *testing.T) {
+	changes := []LocationChange{
+		{Location: Location{ID: 1}, ChangeXID: 5, ChangeSeq: 11},
+		{Location: Location{ID: 2}, IsDeleted: true, ChangeXID: 6, ChangeSeq: 13},
+		{Location: Location{ID: 3}, ChangeXID: 7, ChangeSeq: 12},
+	}
+
+	since := ChangeToken{XID: 4, Seq: 10}
+
+	resp := NewChangesResponse(changes, since, 2)
+	assert.True(t, resp.HasMore)
+	assert.Len(t, resp.Upserts, 1)
+	assert.Equal(t, []int64{2}, resp.Tombstones)
+	assert.Equal(t, EncodeChangeToken(ChangeToken{XID: 6, Seq: 13}), resp.NextToken)
+
+	resp = NewChangesResponse(nil, since, 2)
+	assert.False(t, resp.HasMore)
+	assert.Empty(t, resp.Upserts)
+	assert.Equal(t, EncodeChangeToken(since), resp.NextToken)
+}
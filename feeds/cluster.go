@@ -0,0 +1,115 @@
+package feeds
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	MaxClusterZoom = 20
+
+	// clusters are computed on tiles a few levels deeper than the requested map zoom,
+	// so one map tile holds a handful of bubbles instead of one
+	clusterZoomOffset = 2
+)
+
+type Cluster struct {
+	Key       string         `json:"key"`
+	Latitude  float64        `json:"latitude"`
+	Longitude float64        `json:"longitude"`
+	Count     int            `json:"count"`
+	Reasons   map[string]int `json:"reasons"`
+}
+
+type ClusterResponse struct {
+	Count   int       `json:"count"`
+	Results []Cluster `json:"results"`
+}
+
+// ClusterPrecision returns the web mercator tile zoom used to group locations for the given map zoom.
+func ClusterPrecision(zoom int) int {
+	return zoom + clusterZoomOffset
+}
+
+func TileKey(z, x, y int) string {
+	return fmt.Sprintf("%d/%d/%d", z, x, y)
+}
+
+// ClusterBuilder merges partial aggregates of a tile into clusters with weighted centroids.
+type ClusterBuilder struct {
+	clusters map[string]*clusterAcc
+}
+
+type clusterAcc struct {
+	count          int
+	sumLat, sumLng float64
+	reasons        map[string]int
+}
+
+func NewClusterBuilder() *ClusterBuilder {
+	return &ClusterBuilder{clusters: make(map[string]*clusterAcc)}
+}
+
+func (b *ClusterBuilder) cluster(key string) *clusterAcc {
+	acc, ok := b.clusters[key]
+	if !ok {
+		acc = &clusterAcc{reasons: make(map[string]int)}
+		b.clusters[key] = acc
+	}
+	return acc
+}
+
+// Add accumulates count locations of a tile whose coordinates sum up to sumLat and sumLng.
+// reason is the comma separated reason column of those locations.
+func (b *ClusterBuilder) Add(key string, count int, sumLat, sumLng float64, reason *string) {
+	acc := b.cluster(key)
+	acc.count += count
+	acc.sumLat += sumLat
+	acc.sumLng += sumLng
+
+	if reason == nil {
+		return
+	}
+
+	for _, r := range strings.Split(*reason, ",") {
+		b.AddReason(key, r, count)
+	}
+}
+
+// AddReason counts count locations of a tile under reason.
+func (b *ClusterBuilder) AddReason(key, reason string, count int) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return
+	}
+
+	b.cluster(key).reasons[reason] += count
+}
+
+// Clusters returns the merged clusters, biggest first.
+func (b *ClusterBuilder) Clusters() []Cluster {
+	results := make([]Cluster, 0, len(b.clusters))
+	for key, acc := range b.clusters {
+		if acc.count == 0 {
+			continue
+		}
+
+		results = append(results, Cluster{
+			Key:       key,
+			Latitude:  acc.sumLat / float64(acc.count),
+			Longitude: acc.sumLng / float64(acc.count),
+			Count:     acc.count,
+			Reasons:   acc.reasons,
+		})
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Key < results[j].Key
+	})
+
+	return results
+}
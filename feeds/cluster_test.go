@@ -0,0 +1,28 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClusterBuilder(t *testing.T) {
+	enkaz := "enkaz,su"
+	su := "su"
+
+	builder := NewClusterBuilder()
+	builder.Add("8/150/98", 2, 74.0, 72.0, &enkaz)
+	builder.Add("8/150/98", 1, 37.0, 36.0, &su)
+	builder.Add("8/151/98", 1, 36.5, 36.5, nil)
+
+	clusters := builder.Clusters()
+
+	assert.Len(t, clusters, 2)
+	assert.Equal(t, "8/150/98", clusters[0].Key)
+	assert.Equal(t, 3, clusters[0].Count)
+	assert.InDelta(t, 37.0, clusters[0].Latitude, 1e-9)
+	assert.InDelta(t, 36.0, clusters[0].Longitude, 1e-9)
+	assert.Equal(t, map[string]int{"enkaz": 2, "su": 3}, clusters[0].Reasons)
+	assert.Equal(t, 1, clusters[1].Count)
+	assert.Empty(t, clusters[1].Reasons)
+}
@@ -0,0 +1,68 @@
+package feeds
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultPageSize = 1000
+	MaxPageSize     = 10000
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last location of a page. Pages are ordered by epoch and id descending,
+// so the next page starts right after (Epoch, ID).
+type Cursor struct {
+	Epoch int64
+	ID    int64
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Epoch, 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	epoch, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Epoch: epoch, ID: id}, nil
+}
+
+// NewResponse builds a response page. Readers fetch one row more than limit,
+// so a surplus row means there is a next page.
+func NewResponse(results []Location, limit int) *Response {
+	resp := &Response{}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+		last := results[len(results)-1]
+		resp.NextCursor = Cursor{Epoch: last.Epoch, ID: last.ID}.Encode()
+	}
+
+	resp.Count = len(results)
+	resp.Results = results
+
+	return resp
+}
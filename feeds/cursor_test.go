@@ -0,0 +1,40 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{Epoch: 1675945487, ID: 123456}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+
+	assert.NoError(t, err)
+	assert.Equal(t, cursor, *decoded)
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	_, err := DecodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodeCursor("")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewResponse(t *testing.T) {
+	results := []Location{{ID: 3, Epoch: 30}, {ID: 2, Epoch: 20}, {ID: 1, Epoch: 10}}
+
+	resp := NewResponse(results, 2)
+	assert.Equal(t, 2, resp.Count)
+	assert.Equal(t, Cursor{Epoch: 20, ID: 2}.Encode(), resp.NextCursor)
+
+	resp = NewResponse(results, 3)
+	assert.Equal(t, 3, resp.Count)
+	assert.Empty(t, resp.NextCursor)
+
+	resp = NewResponse(results, 0)
+	assert.Equal(t, 3, resp.Count)
+	assert.Empty(t, resp.NextCursor)
+}
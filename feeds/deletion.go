@@ -0,0 +1,44 @@
+package feeds
+
+import "time"
+
+// Reasons the consumer soft deletes a location for.
+const (
+	DeletionReasonIrrelevant = "irrelevant"
+	DeletionReasonDuplicate  = "duplicate"
+)
+
+// DeletedLocation is an automatically deleted location with the classifier scores it was deleted with.
+type DeletedLocation struct {
+	Location
+	FullText      string             `json:"full_text"`
+	DeletedReason *string            `json:"deleted_reason,omitempty"`
+	DeletedScores map[string]float64 `json:"deleted_scores,omitempty"`
+	DeletedAt     *time.Time         `json:"deleted_at,omitempty"`
+}
+
+type DeletedLocationsResponse struct {
+	Count      int               `json:"count"`
+	Results    []DeletedLocation `json:"results"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+func ValidDeletionReason(reason string) bool {
+	return reason == DeletionReasonIrrelevant || reason == DeletionReasonDuplicate
+}
+
+// NewDeletedLocationsResponse builds a page of deleted locations ordered by id descending,
+// like NewResponse a surplus row means there is a next page.
+func NewDeletedLocationsResponse(results []DeletedLocation, limit int) *DeletedLocationsResponse {
+	resp := &DeletedLocationsResponse{}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+		resp.NextCursor = Cursor{ID: results[len(results)-1].ID}.Encode()
+	}
+
+	resp.Count = len(results)
+	resp.Results = results
+
+	return resp
+}
@@ -0,0 +1,25 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDeletedLocationsResponse(t *testing.T) {
+	results := []DeletedLocation{
+		{Location: Location{ID: 9}},
+		{Location: Location{ID: 7}},
+		{Location: Location{ID: 4}},
+	}
+
+	resp := NewDeletedLocationsResponse(results, 2)
+
+	assert.Equal(t, 2, resp.Count)
+	cursor, err := DecodeCursor(resp.NextCursor)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), cursor.ID)
+
+	resp = NewDeletedLocationsResponse(results[:2], 2)
+	assert.Empty(t, resp.NextCursor)
+}
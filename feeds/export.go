@@ -0,0 +1,115 @@
+package feeds
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+
+	MIMETextCSV = "text/csv; charset=utf-8"
+	MIMEXLSX    = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// ExportHeader names the columns of ExportRow.Values.
+var ExportHeader = []string{
+	"id",
+	"entry_id",
+	"reported_at",
+	"formatted_address",
+	"latitude",
+	"longitude",
+	"reason",
+	"needs",
+	"delivered_needs",
+	"channel",
+	"is_location_verified",
+	"is_need_verified",
+	"is_resolved",
+	"contact_name",
+	"contact_phone",
+}
+
+// ExportRow is a location as it is written to spreadsheets, contact fields are masked.
+type ExportRow struct {
+	Location
+	ContactName  string
+	ContactPhone string
+}
+
+// Values returns the cells of the row in ExportHeader order, text cells are escaped with escapeFormula.
+func (r ExportRow) Values() []interface{} {
+	var open, delivered []string
+	for _, need := range r.Needs {
+		if need.Status {
+			open = append(open, need.Label)
+		} else {
+			delivered = append(delivered, need.Label)
+		}
+	}
+
+	var reportedAt interface{}
+	if r.Epoch > 0 {
+		reportedAt = time.Unix(r.Epoch, 0).UTC()
+	}
+
+	return []interface{}{
+		r.ID,
+		r.EntryID,
+		reportedAt,
+		escapeFormula(r.FormattedAddress),
+		r.Latitude,
+		r.Longitude,
+		escapeFormula(stringOrEmpty(r.Reason)),
+		escapeFormula(strings.Join(open, ", ")),
+		escapeFormula(strings.Join(delivered, ", ")),
+		escapeFormula(stringOrEmpty(r.Channel)),
+		r.IsLocationVerified,
+		r.IsNeedVerified,
+		r.IsResolved,
+		escapeFormula(r.ContactName),
+		escapeFormula(r.ContactPhone),
+	}
+}
+
+// escapeFormula keeps spreadsheet programs from evaluating reported text as a formula
+// by prefixing cells which would start one with a quote.
+func escapeFormula(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+// Record returns the cells of the row as csv fields.
+func (r ExportRow) Record() []string {
+	values := r.Values()
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			record = append(record, "")
+		case int64:
+			record = append(record, strconv.FormatInt(v, 10))
+		case float64:
+			record = append(record, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			record = append(record, strconv.FormatBool(v))
+		case time.Time:
+			record = append(record, v.Format(time.RFC3339))
+		case string:
+			record = append(record, v)
+		}
+	}
+	return record
+}
+
+func stringOrEmpty(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,55 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportRowRecord(t *testing.T) {
+	reason := "enkaz,su"
+	row := ExportRow{
+		Location: Location{
+			ID:               3,
+			EntryID:          3,
+			Epoch:            1675900800,
+			FormattedAddress: "Antakya, Hatay",
+			Latitude:         36.2,
+			Longitude:        36.16,
+			Reason:           &reason,
+			IsNeedVerified:   true,
+			Needs:            []NeedItem{{Label: "su", Status: true}, {Label: "gıda", Status: false}, {Label: "çadır", Status: true}},
+		},
+		ContactPhone: "(53)5555-****",
+	}
+
+	record := row.Record()
+
+	assert.Len(t, record, len(ExportHeader))
+	assert.Equal(t, []string{"3", "3", "2023-02-09T00:00:00Z", "Antakya, Hatay", "36.2", "36.16", "enkaz,su",
+		"su, çadır", "gıda", "", "false", "true", "false", "", "(53)5555-****"}, record)
+}
+
+func TestExportRowEscapesFormulas(t *testing.T) {
+	reason := "=HYPERLINK(\"http://example.com\")"
+	channel := "@twitter"
+	row := ExportRow{
+		Location: Location{
+			ID:               4,
+			FormattedAddress: "+90 Antakya",
+			Reason:           &reason,
+			Channel:          &channel,
+			Needs:            []NeedItem{{Label: "-su", Status: true}},
+		},
+		ContactName: "\tA**",
+	}
+
+	record := row.Record()
+
+	assert.Equal(t, "'+90 Antakya", record[3])
+	assert.Equal(t, "'=HYPERLINK(\"http://example.com\")", record[6])
+	assert.Equal(t, "'-su", record[7])
+	assert.Equal(t, "'@twitter", record[9])
+	assert.Equal(t, "'\tA**", record[13])
+	assert.Equal(t, row.Values()[3], "'+90 Antakya")
+}
@@ -9,17 +9,40 @@ import (
 )
 
 type Feed struct {
-	ID               int64     `json:"id,omitempty"`
-	FullText         string    `json:"full_text"`
-	IsResolved       bool      `json:"is_resolved"`
-	Channel          string    `json:"channel,omitempty"`
-	Timestamp        time.Time `json:"timestamp,omitempty"`
-	Epoch            int64     `json:"epoch"`
-	ExtraParameters  *string   `json:"extra_parameters,omitempty"`
-	FormattedAddress string    `json:"formatted_address,omitempty"`
-	Reason           *string   `json:"reason,omitempty"`
-	Lat              *float64  `json:"lat,omitempty"`
-	Lng              *float64  `json:"lng,omitempty"`
+	ID               int64      `json:"id,omitempty"`
+	FullText         string     `json:"full_text"`
+	IsResolved       bool       `json:"is_resolved"`
+	Channel          string     `json:"channel,omitempty"`
+	Timestamp        time.Time  `json:"timestamp,omitempty"`
+	Epoch            int64      `json:"epoch"`
+	ExtraParameters  *string    `json:"extra_parameters,omitempty"`
+	FormattedAddress string     `json:"formatted_address,omitempty"`
+	Reason           *string    `json:"reason,omitempty"`
+	Lat              *float64   `json:"lat,omitempty"`
+	Lng              *float64   `json:"lng,omitempty"`
+	ResolvedBy       *string    `json:"resolved_by,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	ResolvedOutcome  *string    `json:"resolved_outcome,omitempty"`
+	Locations        []Location `json:"locations,omitempty"`
+	// ClusterID is the id of the canonical location when other reports were found to be duplicates
+	// of the same place, ClusterMembers lists every report of the cluster, canonical report first
+	ClusterID      *int64          `json:"cluster_id,omitempty"`
+	ReportCount    int             `json:"report_count,omitempty"`
+	ClusterMembers []ClusterMember `json:"cluster_members,omitempty"`
+}
+
+type ClusterMember struct {
+	LocationID       int64   `json:"location_id"`
+	EntryID          int64   `json:"entry_id"`
+	FullText         string  `json:"full_text"`
+	Channel          string  `json:"channel,omitempty"`
+	Epoch            int64   `json:"epoch"`
+	FormattedAddress string  `json:"formatted_address"`
+	Reason           *string `json:"reason,omitempty"`
+}
+
+type ResolveFeedRequest struct {
+	Outcome string `json:"outcome"`
 }
 
 type LatLng struct {
@@ -33,8 +56,9 @@ type LiteResult struct {
 }
 
 type Response struct {
-	Count   int        `json:"count"`
-	Results []Location `json:"results"`
+	Count      int        `json:"count"`
+	Results    []Location `json:"results"`
+	NextCursor string     `json:"next_cursor,omitempty"`
 }
 
 type Location struct {
@@ -53,19 +77,25 @@ type Location struct {
 	ExtraParameters    *string    `json:"extra_parameters,omitempty"`
 	IsLocationVerified bool       `json:"is_location_verified,omitempty"`
 	IsNeedVerified     bool       `json:"is_need_verified,omitempty"`
+	IsResolved         bool       `json:"is_resolved,omitempty"`
 	Needs              []NeedItem `json:"needs,omitempty"`
+	ReportCount        int        `json:"report_count,omitempty"`
+	IsStale            bool       `json:"is_stale,omitempty"`
 	Loc                []float64  `json:"loc"`
+	Distance           *float64   `json:"distance_m,omitempty"`
 }
 
 type UpdateFeedLocationsRequest struct {
 	FeedLocations []FeedLocation `json:"feed_locations"`
 }
 
+// FeedLocation corrects the locations of an entry, LocationID limits the correction to one of them.
 type FeedLocation struct {
-	EntryID   int64   `json:"entry_id"`
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
-	Address   string  `json:"address"`
+	EntryID    int64   `json:"entry_id"`
+	LocationID int64   `json:"location_id,omitempty"`
+	Latitude   float64 `json:"latitude"`
+	Longitude  float64 `json:"longitude"`
+	Address    string  `json:"address"`
 }
 
 type NeedItem struct {
@@ -0,0 +1,93 @@
+package feeds
+
+import (
+	"errors"
+	"math"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const (
+	EarthRadiusM = 6371000.0
+
+	MaxRadiusM = 100000.0
+
+	metersPerDegree = 111320.0
+)
+
+var ErrInvalidPolygon = errors.New("polygon must be a GeoJSON Polygon with a single closed ring")
+
+// Distance returns the great-circle distance in meters between two coordinates.
+func Distance(lat1, lng1, lat2, lng2 float64) float64 {
+	dLat := radians(lat2 - lat1)
+	dLng := radians(lng2 - lng1)
+
+	a := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(radians(lat1))*math.Cos(radians(lat2))*math.Pow(math.Sin(dLng/2), 2)
+
+	return EarthRadiusM * 2 * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// RadiusBounds returns a bounding box containing the circle around lat, lng.
+// It is used to narrow down rows before the exact distance is computed.
+func RadiusBounds(lat, lng, radiusM float64) (minLat, minLng, maxLat, maxLng float64) {
+	latDelta := radiusM / metersPerDegree
+	lngDelta := 180.0
+	if cos := math.Cos(radians(lat)); cos > 1e-6 {
+		lngDelta = math.Min(radiusM/(metersPerDegree*cos), 180)
+	}
+
+	return lat - latDelta, lng - lngDelta, lat + latDelta, lng + lngDelta
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// ParsePolygon reads a GeoJSON Polygon geometry and returns its outer ring as [longitude, latitude] pairs.
+// Polygons with holes are rejected since the postgres read path can not evaluate them.
+func ParsePolygon(raw string) ([][]float64, error) {
+	var geometry struct {
+		Type        string        `json:"type"`
+		Coordinates [][][]float64 `json:"coordinates"`
+	}
+
+	if err := jsoniter.UnmarshalFromString(raw, &geometry); err != nil {
+		return nil, ErrInvalidPolygon
+	}
+
+	if geometry.Type != "Polygon" || len(geometry.Coordinates) != 1 {
+		return nil, ErrInvalidPolygon
+	}
+
+	ring := geometry.Coordinates[0]
+	if len(ring) < 4 {
+		return nil, ErrInvalidPolygon
+	}
+
+	for _, point := range ring {
+		if len(point) != 2 || point[0] < -180 || point[0] > 180 || point[1] < -90 || point[1] > 90 {
+			return nil, ErrInvalidPolygon
+		}
+	}
+
+	first, last := ring[0], ring[len(ring)-1]
+	if first[0] != last[0] || first[1] != last[1] {
+		return nil, ErrInvalidPolygon
+	}
+
+	return ring, nil
+}
+
+// PolygonBounds returns the bounding box of a ring of [longitude, latitude] pairs.
+func PolygonBounds(ring [][]float64) (minLat, minLng, maxLat, maxLng float64) {
+	minLat, minLng = math.Inf(1), math.Inf(1)
+	maxLat, maxLng = math.Inf(-1), math.Inf(-1)
+
+	for _, point := range ring {
+		minLng, maxLng = math.Min(minLng, point[0]), math.Max(maxLng, point[0])
+		minLat, maxLat = math.Min(minLat, point[1]), math.Max(maxLat, point[1])
+	}
+
+	return minLat, minLng, maxLat, maxLng
+}
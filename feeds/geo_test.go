@@ -0,0 +1,45 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	// one degree along a meridian
+	assert.InDelta(t, 111195, Distance(36, 36, 37, 36), 1)
+	assert.Equal(t, 0.0, Distance(37, 37, 37, 37))
+}
+
+func TestRadiusBounds(t *testing.T) {
+	minLat, minLng, maxLat, maxLng := RadiusBounds(37, 37, 2000)
+
+	assert.InDelta(t, 2000, Distance(37, 37, maxLat, 37), 10)
+	assert.InDelta(t, 2000, Distance(37, 37, minLat, 37), 10)
+	assert.InDelta(t, 2000, Distance(37, 37, 37, maxLng), 10)
+	assert.InDelta(t, 2000, Distance(37, 37, 37, minLng), 10)
+}
+
+func TestParsePolygon(t *testing.T) {
+	ring, err := ParsePolygon(`{"type":"Polygon","coordinates":[[[36,36],[37,36],[37,37],[36,36]]]}`)
+
+	assert.NoError(t, err)
+	assert.Len(t, ring, 4)
+
+	minLat, minLng, maxLat, maxLng := PolygonBounds(ring)
+	assert.Equal(t, []float64{36, 36, 37, 37}, []float64{minLat, minLng, maxLat, maxLng})
+}
+
+func TestParsePolygonInvalid(t *testing.T) {
+	for _, raw := range []string{
+		`not json`,
+		`{"type":"Point","coordinates":[36,36]}`,
+		`{"type":"Polygon","coordinates":[[[36,36],[37,36],[37,37],[36,37]]]}`,
+		`{"type":"Polygon","coordinates":[[[36,36],[37,36],[36,36]]]}`,
+		`{"type":"Polygon","coordinates":[[[36,36],[37,36],[37,37],[36,36]],[[36.1,36.1],[36.2,36.1],[36.2,36.2],[36.1,36.1]]]}`,
+	} {
+		_, err := ParsePolygon(raw)
+		assert.ErrorIs(t, err, ErrInvalidPolygon, raw)
+	}
+}
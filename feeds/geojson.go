@@ -0,0 +1,126 @@
+package feeds
+
+const MIMEApplicationGeoJSON = "application/geo+json"
+
+type FeatureCollection struct {
+	Type       string    `json:"type"`
+	Features   []Feature `json:"features"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// Feature is a GeoJSON feature, a feature without a location has a null geometry.
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         int64                  `json:"id"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON point, coordinates are in [longitude, latitude] order.
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewPoint(lat, lng float64) *Geometry {
+	return &Geometry{
+		Type:        "Point",
+		Coordinates: []float64{lng, lat},
+	}
+}
+
+func (l Location) Feature() Feature {
+	properties := map[string]interface{}{
+		"entry_id":             l.EntryID,
+		"epoch":                l.Epoch,
+		"is_location_verified": l.IsLocationVerified,
+		"is_need_verified":     l.IsNeedVerified,
+		"is_resolved":          l.IsResolved,
+	}
+
+	if l.FormattedAddress != "" {
+		properties["formatted_address"] = l.FormattedAddress
+	}
+	if l.Reason != nil {
+		properties["reason"] = *l.Reason
+	}
+	if l.Channel != nil {
+		properties["channel"] = *l.Channel
+	}
+	if l.ExtraParameters != nil {
+		properties["extra_parameters"] = *l.ExtraParameters
+	}
+	if len(l.Needs) > 0 {
+		properties["needs"] = l.Needs
+	}
+	if l.ReportCount > 1 {
+		properties["report_count"] = l.ReportCount
+	}
+
+	return Feature{
+		Type:       "Feature",
+		ID:         l.ID,
+		Geometry:   NewPoint(l.Latitude, l.Longitude),
+		Properties: properties,
+	}
+}
+
+func (r *Response) FeatureCollection() FeatureCollection {
+	features := make([]Feature, 0, len(r.Results))
+	for _, location := range r.Results {
+		features = append(features, location.Feature())
+	}
+
+	return FeatureCollection{
+		Type:       "FeatureCollection",
+		Features:   features,
+		NextCursor: r.NextCursor,
+	}
+}
+
+func (f Feed) Feature() Feature {
+	properties := map[string]interface{}{
+		"full_text":   f.FullText,
+		"is_resolved": f.IsResolved,
+		"epoch":       f.Epoch,
+		"timestamp":   f.Timestamp,
+	}
+
+	if f.Channel != "" {
+		properties["channel"] = f.Channel
+	}
+	if f.FormattedAddress != "" {
+		properties["formatted_address"] = f.FormattedAddress
+	}
+	if f.Reason != nil {
+		properties["reason"] = *f.Reason
+	}
+	if f.ExtraParameters != nil {
+		properties["extra_parameters"] = *f.ExtraParameters
+	}
+	if f.ResolvedBy != nil {
+		properties["resolved_by"] = *f.ResolvedBy
+	}
+	if f.ResolvedAt != nil {
+		properties["resolved_at"] = *f.ResolvedAt
+	}
+	if f.ResolvedOutcome != nil {
+		properties["resolved_outcome"] = *f.ResolvedOutcome
+	}
+	if f.ClusterID != nil {
+		properties["cluster_id"] = *f.ClusterID
+		properties["report_count"] = f.ReportCount
+	}
+
+	var geometry *Geometry
+	if f.Lat != nil && f.Lng != nil {
+		geometry = NewPoint(*f.Lat, *f.Lng)
+	}
+
+	return Feature{
+		Type:       "Feature",
+		ID:         f.ID,
+		Geometry:   geometry,
+		Properties: properties,
+	}
+}
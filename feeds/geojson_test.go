@@ -0,0 +1,61 @@
+package feeds
+
+import (
+	"testing"
+
+	jsoniter "github.com/json-iterator/go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocationFeature(t *testing.T) {
+	reason := "enkaz,su"
+	channel := "twitter"
+	location := Location{
+		ID:             1,
+		Latitude:       36.2,
+		Longitude:      36.1,
+		EntryID:        1,
+		Reason:         &reason,
+		Channel:        &channel,
+		IsNeedVerified: true,
+		Needs:          []NeedItem{{Label: "su", Status: true}},
+	}
+
+	feature := location.Feature()
+
+	assert.Equal(t, "Feature", feature.Type)
+	assert.Equal(t, []float64{36.1, 36.2}, feature.Geometry.Coordinates)
+	assert.Equal(t, "enkaz,su", feature.Properties["reason"])
+	assert.Equal(t, "twitter", feature.Properties["channel"])
+	assert.Equal(t, true, feature.Properties["is_need_verified"])
+	assert.Equal(t, false, feature.Properties["is_location_verified"])
+	assert.NotContains(t, feature.Properties, "extra_parameters")
+	assert.NotContains(t, feature.Properties, "report_count")
+
+	location.ReportCount = 40
+	assert.Equal(t, 40, location.Feature().Properties["report_count"])
+}
+
+func TestFeatureCollection(t *testing.T) {
+	resp := &Response{Count: 1, Results: []Location{{ID: 1, Latitude: 1, Longitude: 2}}}
+
+	b, err := jsoniter.Marshal(resp.FeatureCollection())
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"type":"FeatureCollection","features":[{"type":"Feature","id":1,
+		"geometry":{"type":"Point","coordinates":[2,1]},
+		"properties":{"entry_id":0,"epoch":0,"is_location_verified":false,"is_need_verified":false,"is_resolved":false}}]}`, string(b))
+}
+
+func TestFeedFeatureGeometry(t *testing.T) {
+	lat, lng := 36.2, 36.1
+	feed := Feed{ID: 1, FullText: "yardım", Lat: &lat, Lng: &lng}
+
+	assert.Equal(t, []float64{36.1, 36.2}, feed.Feature().Geometry.Coordinates)
+
+	feed.Lat, feed.Lng = nil, nil
+	b, err := jsoniter.Marshal(feed.Feature())
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"geometry":null`)
+}
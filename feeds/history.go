@@ -0,0 +1,35 @@
+package feeds
+
+import "time"
+
+const (
+	LocationActionCorrection = "correction"
+	LocationActionRollback   = "rollback"
+)
+
+// LocationSnapshot is the corrected part of a feeds_location row at one point in time.
+type LocationSnapshot struct {
+	Latitude           float64 `json:"latitude"`
+	Longitude          float64 `json:"longitude"`
+	FormattedAddress   string  `json:"formatted_address"`
+	IsLocationVerified bool    `json:"is_location_verified"`
+}
+
+// LocationCorrection is an audited change of a location, Previous is what it replaced.
+// Rollbacks point at the correction they undid with RolledBackID.
+type LocationCorrection struct {
+	ID           int64            `json:"id"`
+	LocationID   int64            `json:"location_id"`
+	EntryID      int64            `json:"entry_id"`
+	Action       string           `json:"action"`
+	Previous     LocationSnapshot `json:"previous"`
+	Current      LocationSnapshot `json:"current"`
+	ChangedBy    string           `json:"changed_by"`
+	ChangedAt    time.Time        `json:"changed_at"`
+	RolledBackID *int64           `json:"rolled_back_id,omitempty"`
+}
+
+type LocationHistoryResponse struct {
+	Count   int                  `json:"count"`
+	Results []LocationCorrection `json:"results"`
+}
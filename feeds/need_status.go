@@ -0,0 +1,54 @@
+package feeds
+
+import (
+	"strings"
+	"time"
+)
+
+// UpdateNeedStatusRequest sets NeedItem.Status, true while the need is open and false once it has been delivered.
+type UpdateNeedStatusRequest struct {
+	Status *bool `json:"status"`
+}
+
+// NeedStatusChange is a recorded status update of a need label on a location.
+type NeedStatusChange struct {
+	ID             int64     `json:"id"`
+	LocationID     int64     `json:"location_id"`
+	EntryID        int64     `json:"entry_id"`
+	Label          string    `json:"label"`
+	PreviousStatus bool      `json:"previous_status"`
+	Status         bool      `json:"status"`
+	ChangedBy      string    `json:"changed_by"`
+	ChangedAt      time.Time `json:"changed_at"`
+}
+
+type NeedHistoryResponse struct {
+	Count   int                `json:"count"`
+	Results []NeedStatusChange `json:"results"`
+}
+
+// SetNeedStatus sets the status of the need with label, labels are matched case insensitively.
+// It returns the stored label and its previous status, ok is false when needs has no such label.
+func SetNeedStatus(needs []NeedItem, label string, status bool) (storedLabel string, previous bool, ok bool) {
+	for i := range needs {
+		if strings.EqualFold(needs[i].Label, label) {
+			previous = needs[i].Status
+			needs[i].Status = status
+			return needs[i].Label, previous, true
+		}
+	}
+	return "", false, false
+}
+
+// Satisfied reports whether a location has needs and none of them is open anymore.
+func Satisfied(needs []NeedItem) bool {
+	if len(needs) == 0 {
+		return false
+	}
+	for _, need := range needs {
+		if need.Status {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,28 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetNeedStatus(t *testing.T) {
+	needs := []NeedItem{{Label: "su", Status: true}, {Label: "çadır", Status: true}}
+
+	label, previous, ok := SetNeedStatus(needs, "SU", false)
+
+	assert.True(t, ok)
+	assert.Equal(t, "su", label)
+	assert.True(t, previous)
+	assert.False(t, needs[0].Status)
+	assert.True(t, needs[1].Status)
+
+	_, _, ok = SetNeedStatus(needs, "battaniye", false)
+	assert.False(t, ok)
+}
+
+func TestSatisfied(t *testing.T) {
+	assert.False(t, Satisfied(nil))
+	assert.False(t, Satisfied([]NeedItem{{Label: "su", Status: false}, {Label: "çadır", Status: true}}))
+	assert.True(t, Satisfied([]NeedItem{{Label: "su", Status: false}, {Label: "çadır", Status: false}}))
+}
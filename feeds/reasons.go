@@ -0,0 +1,74 @@
+package feeds
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Reasons is the taxonomy served by /reasons, feed locations and needs are categorised with it.
+var Reasons = []string{
+	"barınma",
+	"battaniye",
+	"ekip",
+	"elektrik",
+	"elektronik",
+	"enkaz",
+	"erzak",
+	"genel",
+	"giyecek",
+	"giyim",
+	"giysi",
+	"guvenli-noktalar",
+	"gıda",
+	"hayvanlar-icin-tedavi",
+	"hijyen",
+	"ilaç",
+	"ısınma",
+	"kefen",
+	"kişisel bakım",
+	"kişisel ihtiyaç",
+	"konaklama",
+	"kurtarma",
+	"lojistik",
+	"operatör",
+	"pet",
+	"sağlık",
+	"su",
+	"teçhizat",
+	"ulaşım",
+	"yakıt",
+	"yemek",
+	"çadır",
+	"çocuk ihtiyaçları",
+	"ısınma",
+}
+
+// reasonsByKey maps the folded form of every reason in Reasons to its spelling there.
+var reasonsByKey = func() map[string]string {
+	reasons := make(map[string]string, len(Reasons))
+	for _, r := range Reasons {
+		reasons[reasonKey(r)] = r
+	}
+	return reasons
+}()
+
+// reasonKey lower cases a reason with the turkish casing rules and folds dotless ı into i, ASCII input
+// writes I for both of them.
+func reasonKey(reason string) string {
+	return strings.ReplaceAll(strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(reason)), "ı", "i")
+}
+
+// NormalizeReason trims and lower cases a reason and returns its spelling in Reasons, so SAĞLIK is sağlık,
+// ISINMA is ısınma and HIJYEN is hijyen. Reasons which are not in Reasons are only trimmed and lower cased.
+func NormalizeReason(reason string) string {
+	if r, ok := reasonsByKey[reasonKey(reason)]; ok {
+		return r
+	}
+	return strings.ToLowerSpecial(unicode.TurkishCase, strings.TrimSpace(reason))
+}
+
+// ValidReason reports whether reason is in Reasons, reasons are matched case insensitively.
+func ValidReason(reason string) bool {
+	_, ok := reasonsByKey[reasonKey(reason)]
+	return ok
+}
@@ -0,0 +1,25 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidReason(t *testing.T) {
+	assert.True(t, ValidReason(" su "))
+	assert.True(t, ValidReason("SAĞLIK"))
+	assert.True(t, ValidReason("ISINMA"))
+	assert.True(t, ValidReason("İlaç"))
+	assert.True(t, ValidReason("ILAÇ"))
+	assert.True(t, ValidReason("HIJYEN"))
+	assert.False(t, ValidReason("bilinmeyen"))
+}
+
+func TestNormalizeReason(t *testing.T) {
+	assert.Equal(t, "hijyen", NormalizeReason(" HIJYEN "))
+	assert.Equal(t, "ilaç", NormalizeReason("ILAÇ"))
+	assert.Equal(t, "ısınma", NormalizeReason("ISINMA"))
+	assert.Equal(t, "ısınma", NormalizeReason("isinma"))
+	assert.Equal(t, "bilinmeyen", NormalizeReason("BİLİNMEYEN"))
+}
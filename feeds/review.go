@@ -0,0 +1,53 @@
+package feeds
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	DefaultReviewClaimSize = 10
+	MaxReviewClaimSize     = 50
+)
+
+// ReviewItem is a location claimed by a reviewer together with the text its intents and needs were resolved from.
+type ReviewItem struct {
+	Location
+	FullText     string    `json:"full_text"`
+	ClaimedBy    string    `json:"claimed_by"`
+	ClaimedUntil time.Time `json:"claimed_until"`
+}
+
+type ReviewQueueResponse struct {
+	Count   int          `json:"count"`
+	Results []ReviewItem `json:"results"`
+}
+
+// SubmitReviewRequest confirms the resolved intents and needs of a location, fields that are set replace them.
+type SubmitReviewRequest struct {
+	Reason *string    `json:"reason,omitempty"`
+	Needs  []NeedItem `json:"needs,omitempty"`
+}
+
+// ReviewResult is the verified state of a location after a review was submitted.
+type ReviewResult struct {
+	LocationID     int64      `json:"location_id"`
+	EntryID        int64      `json:"entry_id"`
+	Reason         *string    `json:"reason,omitempty"`
+	Needs          []NeedItem `json:"needs,omitempty"`
+	IsNeedVerified bool       `json:"is_need_verified"`
+	VerifiedBy     string     `json:"verified_by"`
+	VerifiedAt     time.Time  `json:"verified_at"`
+}
+
+// NormalizeReasons trims a comma separated reason list and drops empty items.
+func NormalizeReasons(reason string) string {
+	items := strings.Split(reason, ",")
+	normalized := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			normalized = append(normalized, item)
+		}
+	}
+	return strings.Join(normalized, ",")
+}
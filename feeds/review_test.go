@@ -0,0 +1,12 @@
+package feeds
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeReasons(t *testing.T) {
+	assert.Equal(t, "enkaz,su", NormalizeReasons(" enkaz, ,su,"))
+	assert.Equal(t, "", NormalizeReasons(" , "))
+}
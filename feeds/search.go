@@ -0,0 +1,21 @@
+package feeds
+
+const (
+	DefaultSearchSize = 100
+	MaxSearchSize     = 1000
+
+	HighlightPreTag  = "<em>"
+	HighlightPostTag = "</em>"
+)
+
+// SearchResult is a location whose feed text matched a search, Highlights are the matching
+// fragments of the text with the matched terms wrapped in HighlightPreTag and HighlightPostTag.
+type SearchResult struct {
+	Location
+	Highlights []string `json:"highlights,omitempty"`
+}
+
+type SearchResponse struct {
+	Count   int            `json:"count"`
+	Results []SearchResult `json:"results"`
+}
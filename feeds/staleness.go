@@ -0,0 +1,120 @@
+package feeds
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stale filter values of the stale query parameter, stale locations are listed unless clients exclude them.
+const (
+	StaleExclude = "exclude"
+	StaleInclude = "include"
+	StaleOnly    = "only"
+)
+
+var (
+	ErrInvalidStaleFilter     = errors.New("stale must be exclude, include or only")
+	ErrInvalidStalenessPolicy = errors.New("staleness policy must look like reason:duration,reason:duration")
+)
+
+// ValidStaleFilter reports whether s is a stale filter value, empty selects StaleInclude.
+func ValidStaleFilter(s string) bool {
+	switch s {
+	case "", StaleExclude, StaleInclude, StaleOnly:
+		return true
+	}
+	return false
+}
+
+// StalenessPolicy is how long a location of a reason stays current after it was last reported.
+type StalenessPolicy map[string]time.Duration
+
+// DefaultStalenessPolicy expires rescue requests after three days, supplies are asked for longer.
+// Reasons without a duration never go stale.
+var DefaultStalenessPolicy = StalenessPolicy{
+	"enkaz":     72 * time.Hour,
+	"kurtarma":  72 * time.Hour,
+	"sağlık":    7 * 24 * time.Hour,
+	"ilaç":      7 * 24 * time.Hour,
+	"su":        14 * 24 * time.Hour,
+	"gıda":      14 * 24 * time.Hour,
+	"erzak":     14 * 24 * time.Hour,
+	"yemek":     14 * 24 * time.Hour,
+	"barınma":   14 * 24 * time.Hour,
+	"çadır":     14 * 24 * time.Hour,
+	"ısınma":    14 * 24 * time.Hour,
+	"battaniye": 14 * 24 * time.Hour,
+	"giyim":     14 * 24 * time.Hour,
+	"giysi":     14 * 24 * time.Hour,
+	"giyecek":   14 * 24 * time.Hour,
+	"hijyen":    14 * 24 * time.Hour,
+}
+
+// ParseStalenessPolicy parses "reason:duration" pairs separated by commas on top of base,
+// durations are time.ParseDuration strings and a duration of 0 keeps the reason from going stale.
+// Reasons are stored with NormalizeReason so they match the reasons of locations.
+func ParseStalenessPolicy(s string, base StalenessPolicy) (StalenessPolicy, error) {
+	policy := make(StalenessPolicy, len(base))
+	for reason, ttl := range base {
+		policy[reason] = ttl
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return policy, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		reason, ttlStr, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, ErrInvalidStalenessPolicy
+		}
+
+		ttl, err := time.ParseDuration(strings.TrimSpace(ttlStr))
+		if err != nil || ttl < 0 {
+			return nil, ErrInvalidStalenessPolicy
+		}
+
+		reason = NormalizeReason(reason)
+		if ttl == 0 {
+			delete(policy, reason)
+			continue
+		}
+		policy[reason] = ttl
+	}
+
+	return policy, nil
+}
+
+// Of returns how long a location with a comma separated reason list stays current, the longest
+// lasting reason wins. ok is false when none of the reasons go stale.
+func (p StalenessPolicy) Of(reason *string) (ttl time.Duration, ok bool) {
+	if reason == nil {
+		return 0, false
+	}
+
+	for _, r := range strings.Split(*reason, ",") {
+		if d, found := p[NormalizeReason(r)]; found && d > ttl {
+			ttl, ok = d, true
+		}
+	}
+
+	return ttl, ok
+}
+
+// Reasons returns the reasons of the policy and their durations in the same, reason sorted order.
+func (p StalenessPolicy) Reasons() ([]string, []time.Duration) {
+	reasons := make([]string, 0, len(p))
+	for reason := range p {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+
+	ttls := make([]time.Duration, 0, len(reasons))
+	for _, reason := range reasons {
+		ttls = append(ttls, p[reason])
+	}
+
+	return reasons, ttls
+}
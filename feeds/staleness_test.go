@@ -0,0 +1,56 @@
+package feeds
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStalenessPolicyOf(t *testing.T) {
+	policy := StalenessPolicy{"enkaz": 72 * time.Hour, "su": 14 * 24 * time.Hour}
+	mixed := "enkaz,su"
+	enkaz := "enkaz"
+	unknown := "elektrik"
+
+	ttl, ok := policy.Of(&mixed)
+	assert.True(t, ok)
+	assert.Equal(t, 14*24*time.Hour, ttl)
+
+	ttl, ok = policy.Of(&enkaz)
+	assert.True(t, ok)
+	assert.Equal(t, 72*time.Hour, ttl)
+
+	_, ok = policy.Of(&unknown)
+	assert.False(t, ok)
+
+	_, ok = policy.Of(nil)
+	assert.False(t, ok)
+}
+
+func TestParseStalenessPolicy(t *testing.T) {
+	policy, err := ParseStalenessPolicy("kurtarma:48h, su:0", StalenessPolicy{"su": time.Hour, "enkaz": 72 * time.Hour})
+
+	assert.NoError(t, err)
+	assert.Equal(t, StalenessPolicy{"kurtarma": 48 * time.Hour, "enkaz": 72 * time.Hour}, policy)
+
+	reasons, ttls := policy.Reasons()
+	assert.Equal(t, []string{"enkaz", "kurtarma"}, reasons)
+	assert.Equal(t, []time.Duration{72 * time.Hour, 48 * time.Hour}, ttls)
+
+	policy, err = ParseStalenessPolicy("HIJYEN:24h", nil)
+	assert.NoError(t, err)
+	assert.Equal(t, StalenessPolicy{"hijyen": 24 * time.Hour}, policy)
+
+	_, err = ParseStalenessPolicy("su", nil)
+	assert.ErrorIs(t, err, ErrInvalidStalenessPolicy)
+
+	_, err = ParseStalenessPolicy("su:-1h", nil)
+	assert.ErrorIs(t, err, ErrInvalidStalenessPolicy)
+}
+
+func TestValidStaleFilter(t *testing.T) {
+	assert.True(t, ValidStaleFilter(""))
+	assert.True(t, ValidStaleFilter(StaleOnly))
+	assert.False(t, ValidStaleFilter("yes"))
+}
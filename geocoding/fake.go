@@ -0,0 +1,40 @@
+package geocoding
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+// Fake is an in-memory Geocoder for tests and local runs. Addresses are matched case insensitively,
+// unknown addresses return ErrNotFound and addresses in Errors return their error.
+type Fake struct {
+	mu      sync.Mutex
+	Results map[string]Result
+	Errors  map[string]error
+	Calls   []string
+}
+
+func NewFake(results map[string]Result) *Fake {
+	f := &Fake{Results: map[string]Result{}, Errors: map[string]error{}}
+	for address, result := range results {
+		f.Results[strings.ToLower(address)] = result
+	}
+	return f
+}
+
+func (f *Fake) Geocode(_ context.Context, address string) (*Result, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Calls = append(f.Calls, address)
+	key := strings.ToLower(address)
+
+	if err, ok := f.Errors[key]; ok {
+		return nil, err
+	}
+	if result, ok := f.Results[key]; ok {
+		return &result, nil
+	}
+	return nil, ErrNotFound
+}
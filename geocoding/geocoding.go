@@ -0,0 +1,120 @@
+// Package geocoding resolves the free text addresses of needs into coordinates. Geocoder implementations
+// are pluggable, the Worker runs them in the background for needs waiting to be geocoded.
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
+const googleGeocodeURL = "https://maps.googleapis.com/maps/api/geocode/json"
+
+// ErrNotFound is returned by a Geocoder when the address does not resolve to a place, retrying will not help.
+var ErrNotFound = errors.New("address could not be geocoded")
+
+type Result struct {
+	FormattedAddress string
+	Latitude         float64
+	Longitude        float64
+}
+
+type Geocoder interface {
+	Geocode(ctx context.Context, address string) (*Result, error)
+}
+
+// NewFromEnv returns the Google geocoder when GEOCODER_API_KEY is set, GEOCODER_API_URL overrides the
+// Google endpoint. It returns nil without a key.
+func NewFromEnv() Geocoder {
+	apiKey := os.Getenv("GEOCODER_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+
+	geocoder := NewGoogleGeocoder(apiKey)
+	if apiURL := os.Getenv("GEOCODER_API_URL"); apiURL != "" {
+		geocoder.url = apiURL
+	}
+	return geocoder
+}
+
+// GoogleGeocoder uses the Google Maps geocoding API, results are biased towards Turkey.
+type GoogleGeocoder struct {
+	apiKey string
+	url    string
+	client *http.Client
+}
+
+func NewGoogleGeocoder(apiKey string) *GoogleGeocoder {
+	return &GoogleGeocoder{
+		apiKey: apiKey,
+		url:    googleGeocodeURL,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type googleResponse struct {
+	Status  string `json:"status"`
+	Results []struct {
+		FormattedAddress string `json:"formatted_address"`
+		Geometry         struct {
+			Location struct {
+				Lat float64 `json:"lat"`
+				Lng float64 `json:"lng"`
+			} `json:"location"`
+		} `json:"geometry"`
+	} `json:"results"`
+}
+
+func (g *GoogleGeocoder) Geocode(ctx context.Context, address string) (*Result, error) {
+	query := url.Values{}
+	query.Set("address", address)
+	query.Set("region", "tr")
+	query.Set("language", "tr")
+	query.Set("key", g.apiKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.url+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare geocode request: %w", err)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("could not get geocode response: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocode request failed with status code %d", resp.StatusCode)
+	}
+
+	var body googleResponse
+	if err := jsoniter.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("could not decode geocode response: %w", err)
+	}
+
+	switch body.Status {
+	case "OK":
+	case "ZERO_RESULTS":
+		return nil, ErrNotFound
+	default:
+		return nil, fmt.Errorf("geocode request failed with status %s", body.Status)
+	}
+
+	if len(body.Results) == 0 {
+		return nil, ErrNotFound
+	}
+
+	first := body.Results[0]
+	return &Result{
+		FormattedAddress: first.FormattedAddress,
+		Latitude:         first.Geometry.Location.Lat,
+		Longitude:        first.Geometry.Location.Lng,
+	}, nil
+}
@@ -0,0 +1,110 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/needs"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultInterval   = 5 * time.Second
+	defaultRetryAfter = time.Minute
+
+	batchSize   = 20
+	maxAttempts = 5
+
+	geocodeTimeout = 10 * time.Second
+)
+
+// Store keeps the needs waiting for geocoding, implemented by repository.Repository.
+type Store interface {
+	ClaimNeedGeocodes(ctx context.Context, limit int, retryAfter time.Duration) ([]needs.GeocodeTask, error)
+	SetNeedLocation(ctx context.Context, task needs.GeocodeTask, formattedAddress string, latitude, longitude float64) error
+	SetNeedGeocodeStatus(ctx context.Context, task needs.GeocodeTask, status string) error
+}
+
+// Worker geocodes pending needs. Needs whose address does not resolve are marked not_found, other
+// failures are retried with a growing delay and marked failed after maxAttempts.
+type Worker struct {
+	store      Store
+	geocoder   Geocoder
+	interval   time.Duration
+	retryAfter time.Duration
+}
+
+// NewWorker reads GEOCODE_INTERVAL (a duration, 5s by default), how often pending needs are looked for.
+func NewWorker(store Store, geocoder Geocoder) *Worker {
+	interval := defaultInterval
+	if i, err := time.ParseDuration(os.Getenv("GEOCODE_INTERVAL")); err == nil && i > 0 {
+		interval = i
+	}
+
+	return &Worker{
+		store:      store,
+		geocoder:   geocoder,
+		interval:   interval,
+		retryAfter: defaultRetryAfter,
+	}
+}
+
+// Run geocodes pending needs every interval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		// a full batch means more needs are waiting
+		for w.RunOnce(ctx) == batchSize && ctx.Err() == nil {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce geocodes a batch of pending needs and returns how many it claimed.
+func (w *Worker) RunOnce(ctx context.Context) int {
+	tasks, err := w.store.ClaimNeedGeocodes(ctx, batchSize, w.retryAfter)
+	if err != nil {
+		log.Logger().Error("could not claim needs to geocode", zap.Error(err))
+		return 0
+	}
+
+	for _, task := range tasks {
+		w.geocode(ctx, task)
+	}
+
+	return len(tasks)
+}
+
+func (w *Worker) geocode(ctx context.Context, task needs.GeocodeTask) {
+	geocodeCtx, cancel := context.WithTimeout(ctx, geocodeTimeout)
+	result, err := w.geocoder.Geocode(geocodeCtx, task.Address)
+	cancel()
+
+	switch {
+	case err == nil:
+		err = w.store.SetNeedLocation(ctx, task, result.FormattedAddress, result.Latitude, result.Longitude)
+	case errors.Is(err, ErrNotFound):
+		err = w.store.SetNeedGeocodeStatus(ctx, task, needs.GeocodeStatusNotFound)
+	case task.Attempts >= maxAttempts:
+		log.Logger().Error("giving up geocoding need", zap.Int64("needID", task.ID), zap.Error(err))
+		err = w.store.SetNeedGeocodeStatus(ctx, task, needs.GeocodeStatusFailed)
+	default:
+		// the need stays pending and is claimed again after its retry delay
+		log.Logger().Warn("could not geocode need", zap.Int64("needID", task.ID), zap.Int("attempts", task.Attempts), zap.Error(err))
+		return
+	}
+
+	if err != nil {
+		log.Logger().Error("could not store need geocode", zap.Int64("needID", task.ID), zap.Error(err))
+	}
+}
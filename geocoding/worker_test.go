@@ -0,0 +1,67 @@
+package geocoding
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeStore struct {
+	tasks     []needs.GeocodeTask
+	locations map[int64]Result
+	statuses  map[int64]string
+}
+
+func (s *fakeStore) ClaimNeedGeocodes(_ context.Context, limit int, _ time.Duration) ([]needs.GeocodeTask, error) {
+	if len(s.tasks) < limit {
+		limit = len(s.tasks)
+	}
+	tasks := s.tasks[:limit]
+	s.tasks = s.tasks[limit:]
+	return tasks, nil
+}
+
+func (s *fakeStore) SetNeedLocation(_ context.Context, task needs.GeocodeTask, formattedAddress string, latitude, longitude float64) error {
+	s.locations[task.ID] = Result{FormattedAddress: formattedAddress, Latitude: latitude, Longitude: longitude}
+	s.statuses[task.ID] = needs.GeocodeStatusResolved
+	return nil
+}
+
+func (s *fakeStore) SetNeedGeocodeStatus(_ context.Context, task needs.GeocodeTask, status string) error {
+	s.statuses[task.ID] = status
+	return nil
+}
+
+func TestWorkerRunOnce(t *testing.T) {
+	store := &fakeStore{
+		tasks: []needs.GeocodeTask{
+			{ID: 1, Address: "Antakya Hatay", Attempts: 1},
+			{ID: 2, Address: "nowhere", Attempts: 1},
+			{ID: 3, Address: "timeout", Attempts: 1},
+			{ID: 4, Address: "timeout", Attempts: maxAttempts},
+		},
+		locations: map[int64]Result{},
+		statuses:  map[int64]string{},
+	}
+
+	geocoder := NewFake(map[string]Result{
+		"antakya hatay": {FormattedAddress: "Antakya/Hatay, Türkiye", Latitude: 36.2, Longitude: 36.16},
+	})
+	geocoder.Errors["timeout"] = errors.New("deadline exceeded")
+
+	worker := NewWorker(store, geocoder)
+
+	assert.Equal(t, 4, worker.RunOnce(context.Background()))
+	assert.Equal(t, 0, worker.RunOnce(context.Background()))
+
+	assert.Equal(t, Result{FormattedAddress: "Antakya/Hatay, Türkiye", Latitude: 36.2, Longitude: 36.16}, store.locations[1])
+	assert.Equal(t, needs.GeocodeStatusResolved, store.statuses[1])
+	assert.Equal(t, needs.GeocodeStatusNotFound, store.statuses[2])
+	assert.NotContains(t, store.statuses, int64(3), "a failed attempt below the limit stays pending")
+	assert.Equal(t, needs.GeocodeStatusFailed, store.statuses[4])
+	assert.Equal(t, []string{"Antakya Hatay", "nowhere", "timeout", "timeout"}, geocoder.Calls)
+}
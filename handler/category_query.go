@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseCategoryQuery reads the comma separated category filter, every category has to be one of /reasons.
+func parseCategoryQuery(ctx *fiber.Ctx) ([]string, error) {
+	categoryStr := ctx.Query("category")
+	if categoryStr == "" {
+		return nil, nil
+	}
+
+	var categories []string
+	for _, category := range strings.Split(categoryStr, ",") {
+		category = feeds.NormalizeReason(category)
+		if !feeds.ValidReason(category) {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "category "+category+" is not one of /reasons")
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
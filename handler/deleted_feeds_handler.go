@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// GetDeletedFeedsHandler godoc
+//
+//	@Summary	List feeds deleted automatically as irrelevant or duplicate
+//	@Tags		Admin
+//	@Produce	json
+//	@Success	200		{object}	feeds.DeletedLocationsResponse
+//	@Param		reason	query		string	false	"irrelevant or duplicate"
+//	@Param		limit	query		integer	false	"Page size, 1000 by default, max 10000"
+//	@Param		cursor	query		string	false	"Cursor returned as next_cursor by the previous page"
+//	@Security	ApiKeyAuth
+//	@Router		/admin/feeds/deleted [GET]
+func GetDeletedFeedsHandler(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		reason := ctx.Query("reason")
+		if reason != "" && !feeds.ValidDeletionReason(reason) {
+			return fiber.NewError(fiber.StatusBadRequest, "reason must be irrelevant or duplicate")
+		}
+
+		limit := ctx.QueryInt("limit", feeds.DefaultPageSize)
+		if limit <= 0 {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+		if limit > feeds.MaxPageSize {
+			limit = feeds.MaxPageSize
+		}
+
+		var cursor *feeds.Cursor
+		if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+			c, err := feeds.DecodeCursor(cursorStr)
+			if err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, err.Error())
+			}
+			cursor = c
+		}
+
+		data, err := repo.GetDeletedLocations(ctx.UserContext(), reason, cursor, limit)
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(feeds.NewDeletedLocationsResponse(data, limit))
+	}
+}
+
+// RestoreFeedHandler godoc
+//
+//	@Summary		Restore an automatically deleted feed and index it again
+//	@Description	Responds 503 when the feed could not be indexed, restoring it again retries the indexing.
+//	@Tags			Admin
+//	@Produce		json
+//	@Success		200		{object}	feeds.Response
+//	@Param			id		path		integer	true	"Feed Id"
+//	@Param			X-Actor	header		string	false	"Who restored the feed"
+//	@Security		ApiKeyAuth
+//	@Router			/admin/feeds/{id}/restore [POST]
+func RestoreFeedHandler(repo *repository.Repository, index *search.LocationIndex) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		locations, fullText, err := repo.RestoreFeedLocation(ctx.UserContext(), feedID, auth.Actor(ctx))
+		if errors.Is(err, repository.ErrFeedNotFound) {
+			return ctx.SendStatus(fiber.StatusNotFound)
+		}
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		// the consumer deletes feeds before it indexes them, restored locations are indexed here for the first time
+		for _, location := range locations {
+			if err := index.CreateFeedLocation(ctx.UserContext(), fullText, location); err != nil {
+				log.Logger().Error("could not index restored feed location",
+					zap.Int64("feedID", feedID), zap.Int64("locationID", location.ID), zap.Error(err))
+				return fiber.NewError(fiber.StatusServiceUnavailable, "feed is restored but not searchable yet, retry the request")
+			}
+		}
+
+		return ctx.JSON(feeds.NewResponse(locations, 0))
+	}
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/gofiber/fiber/v2"
+)
+
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+type responseFormat int
+
+const (
+	formatJSON responseFormat = iota
+	formatGeoJSON
+	formatNDJSON
+)
+
+// negotiateFormat reads the response format from the format query or the Accept header.
+func negotiateFormat(ctx *fiber.Ctx) responseFormat {
+	switch ctx.Query("format") {
+	case "geojson":
+		return formatGeoJSON
+	case "ndjson":
+		return formatNDJSON
+	}
+
+	accept := ctx.Get(fiber.HeaderAccept)
+	switch {
+	case strings.Contains(accept, feeds.MIMEApplicationGeoJSON):
+		return formatGeoJSON
+	case strings.Contains(accept, MIMEApplicationNDJSON):
+		return formatNDJSON
+	}
+
+	return formatJSON
+}
+
+// wantsGeoJSON reports whether the client negotiated GeoJSON with the format query or the Accept header.
+func wantsGeoJSON(ctx *fiber.Ctx) bool {
+	return negotiateFormat(ctx) == formatGeoJSON
+}
+
+func sendGeoJSON(ctx *fiber.Ctx, data interface{}) error {
+	if err := ctx.JSON(data); err != nil {
+		return err
+	}
+
+	ctx.Set(fiber.HeaderContentType, feeds.MIMEApplicationGeoJSON)
+	return nil
+}
@@ -1,87 +1,73 @@
 package handler
 
 import (
-	"strconv"
-	"time"
-
-	"github.com/acikkaynak/backend-api-go/search"
-
 	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/reader"
 	"github.com/acikkaynak/backend-api-go/repository"
 	"github.com/gofiber/fiber/v2"
 )
 
 // GetFeedAreas godoc
 //
-//	@Summary	Get Feed areas with query strings
-//	@Tags		Feed
-//	@Produce	json
-//	@Success	200			{object}	[]feeds.Result
-//	@Param		sw_lat		query		number	true	"Sw Lat"
-//	@Param		sw_lng		query		number	true	"Sw Lng"
-//	@Param		ne_lat		query		number	true	"Ne Lat"
-//	@Param		ne_lng		query		number	true	"Ne Lng"
-//	@Param		time_stamp	query		integer	false	"Timestamp"
-//	@Param		reason		query		string	false	"Reason",
-//	@Param		channel		query		string	false	"Channel"
-//	@Router		/feeds/areas [GET]
-func GetFeedAreas(repo *repository.Repository, index *search.LocationIndex) fiber.Handler {
+//	@Summary		Get Feed areas with query strings
+//	@Description	Duplicate reports of a place are returned as one location, report_count tells how many reports it stands for.
+//	@Description	A paged ndjson stream which has a next page ends with a {"next_cursor": "..."} line.
+//	@Tags			Feed
+//	@Produce		json,application/geo+json,application/x-ndjson
+//	@Success		200					{object}	feeds.Response
+//	@Param			sw_lat				query		number	true	"Sw Lat"
+//	@Param			sw_lng				query		number	true	"Sw Lng"
+//	@Param			ne_lat				query		number	true	"Ne Lat"
+//	@Param			ne_lng				query		number	true	"Ne Lng"
+//	@Param			time_stamp			query		integer	false	"Timestamp"
+//	@Param			reason				query		string	false	"Reason",
+//	@Param			channel				query		string	false	"Channel"
+//	@Param			is_resolved			query		boolean	false	"Only resolved or only unresolved feeds"
+//	@Param			exclude_satisfied	query		boolean	false	"Leave out locations whose needs have all been delivered"
+//	@Param			stale				query		string	false	"include (default), exclude or only locations which expired under the staleness policy"
+//	@Param			limit				query		integer	false	"Page size, max 10000"
+//	@Param			cursor				query		string	false	"Cursor returned as next_cursor by the previous page"
+//	@Param			format				query		string	false	"Response format, geojson for a FeatureCollection, ndjson to stream one location per line"
+//	@Param			lat					query		number	false	"Radius filter center latitude"
+//	@Param			lng					query		number	false	"Radius filter center longitude"
+//	@Param			radius_m			query		number	false	"Radius filter in meters, max 100000"
+//	@Param			polygon				query		string	false	"GeoJSON Polygon geometry"
+//	@Param			sort				query		string	false	"distance sorts radius results nearest first"
+//	@Param			backend				query		string	false	"postgres or elastic, elastic falls back to postgres on errors"
+//	@Router			/feeds/areas [GET]
+func GetFeedAreas(repo *repository.Repository, locationReader *reader.Router) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		swLatStr := ctx.Query("sw_lat")
-		swLngStr := ctx.Query("sw_lng")
-		neLatStr := ctx.Query("ne_lat")
-		neLngStr := ctx.Query("ne_lng")
-		timeStampStr := ctx.Query("time_stamp")
-		reason := ctx.Query("reason", "")
-		channel := ctx.Query("channel", "")
-		extraParams := ctx.Query("extraParams", "")
-		isLocationVerified := ctx.Query("is_location_verified", "")
-		isNeedVerified := ctx.Query("is_need_verified", "")
-
-		var timestamp int64
-		if timeStampStr == "" {
-			timestamp = time.Now().AddDate(-1, -1, -1).Unix()
-		} else {
-			timeInt, err := strconv.ParseInt(timeStampStr, 10, 64)
-			if err != nil {
-				timestamp = time.Now().AddDate(-1, -1, -1).Unix()
-			} else {
-				timestamp = timeInt
-			}
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
 		}
 
-		swLat, _ := strconv.ParseFloat(swLatStr, 64)
-		swLng, _ := strconv.ParseFloat(swLngStr, 64)
-		neLat, _ := strconv.ParseFloat(neLatStr, 64)
-		neLng, _ := strconv.ParseFloat(neLngStr, 64)
-
-		extraParamsBool, _ := strconv.ParseBool(extraParams)
+		backend, err := reader.ParseBackend(ctx.Query("backend"))
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
 
-		getLocationsQuery := &repository.GetLocationsQuery{
-			SwLat:              swLat,
-			SwLng:              swLng,
-			NeLat:              neLat,
-			NeLng:              neLng,
-			Timestamp:          timestamp,
-			Reason:             reason,
-			Channel:            channel,
-			ExtraParams:        extraParamsBool,
-			IsLocationVerified: isLocationVerified,
-			IsNeedVerified:     isNeedVerified,
+		if negotiateFormat(ctx) == formatNDJSON {
+			// streaming iterates database rows, it is always served by postgres
+			ctx.Set(reader.HeaderName, string(reader.BackendPostgres))
+			return streamLocations(ctx, repo, getLocationsQuery)
 		}
 
-		/*
-			TODO enable elastic read
-			data, count, err := index.GetLocations(getLocationsQuery)
-		*/
-		data, err := repo.GetLocations(getLocationsQuery)
+		data, servedBy, err := locationReader.GetLocations(ctx.UserContext(), backend, getLocationsQuery)
+		ctx.Set(reader.HeaderName, string(servedBy))
 		if err != nil {
 			return ctx.JSON(err)
 		}
 
-		resp := &feeds.Response{
-			Count:   len(data),
-			Results: data,
+		limit := getLocationsQuery.Limit
+		if getLocationsQuery.SortByDistance {
+			// nearest first results are cut at limit and have no next page
+			limit = 0
+		}
+
+		resp := feeds.NewResponse(data, limit)
+		if wantsGeoJSON(ctx) {
+			return sendGeoJSON(ctx, resp.FeatureCollection())
 		}
 
 		return ctx.JSON(resp)
@@ -9,12 +9,15 @@ import (
 
 // GetFeedById godoc
 //
-//	@Summary	Get Feeds with given id
-//	@Tags		Feed
-//	@Produce	json
-//	@Success	200	{object}	feeds.Feed
-//	@Param		id	path		integer	true	"Feed Id"
-//	@Router		/feeds/{id} [GET]
+//	@Summary		Get Feeds with given id
+//	@Description	Feeds in a duplicate cluster list every report of the cluster in cluster_members.
+//	@Description	The id of any location of an entry returns the entry, described by that location.
+//	@Tags			Feed
+//	@Produce		json,application/geo+json
+//	@Success		200		{object}	feeds.Feed
+//	@Param			id		path		integer	true	"Feed Id or location id"
+//	@Param			format	query		string	false	"Response format, geojson for a Feature"
+//	@Router			/feeds/{id} [GET]
 func GetFeedById(repo *repository.Repository) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		feedIDStr := ctx.Params("id")
@@ -29,6 +32,10 @@ func GetFeedById(repo *repository.Repository) fiber.Handler {
 			return ctx.JSON(err)
 		}
 
+		if wantsGeoJSON(ctx) {
+			return sendGeoJSON(ctx, feed.Feature())
+		}
+
 		return ctx.JSON(feed)
 	}
 }
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetFeedChanges godoc
+//
+//	@Summary		Get feed locations changed since a change token
+//	@Description	Returns upserted locations and ids of deleted ones. Start without a token, then pass next_token of the previous response; keep asking while has_more is true.
+//	@Tags			Feed
+//	@Produce		json
+//	@Success		200		{object}	feeds.ChangesResponse
+//	@Param			since	query		string	false	"Change token"
+//	@Param			limit	query		integer	false	"Max changes, max 10000"
+//	@Router			/feeds/changes [GET]
+func GetFeedChanges(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		since, err := feeds.DecodeChangeToken(ctx.Query("since"))
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		limit := ctx.QueryInt("limit", feeds.DefaultPageSize)
+		if limit <= 0 {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+		if limit > feeds.MaxPageSize {
+			limit = feeds.MaxPageSize
+		}
+
+		changes, err := repo.GetLocationChanges(ctx.UserContext(), since, limit)
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(feeds.NewChangesResponse(changes, since, limit))
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/reader"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetFeedClusters godoc
+//
+//	@Summary	Get clustered feed locations for a map zoom level
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200			{object}	feeds.ClusterResponse
+//	@Param		zoom		query		integer	true	"Map zoom level, 0-20"
+//	@Param		sw_lat		query		number	false	"Sw Lat"
+//	@Param		sw_lng		query		number	false	"Sw Lng"
+//	@Param		ne_lat		query		number	false	"Ne Lat"
+//	@Param		ne_lng		query		number	false	"Ne Lng"
+//	@Param		time_stamp	query		integer	false	"Timestamp"
+//	@Param		reason		query		string	false	"Reason"
+//	@Param		channel		query		string	false	"Channel"
+//	@Param		backend		query		string	false	"postgres or elastic, elastic falls back to postgres on errors"
+//	@Router		/feeds/clusters [GET]
+func GetFeedClusters(locationReader *reader.Router) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		zoom, err := strconv.Atoi(ctx.Query("zoom"))
+		if err != nil || zoom < 0 || zoom > feeds.MaxClusterZoom {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
+		}
+
+		backend, err := reader.ParseBackend(ctx.Query("backend"))
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		data, servedBy, err := locationReader.GetLocationClusters(ctx.UserContext(), backend, getLocationsQuery, zoom)
+		ctx.Set(reader.HeaderName, string(servedBy))
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(&feeds.ClusterResponse{
+			Count:   len(data),
+			Results: data,
+		})
+	}
+}
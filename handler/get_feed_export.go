@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/pkg/xlsx"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// exports are only started with the api key and may run longer than public streams
+const exportTimeout = 10 * time.Minute
+
+// GetFeedExport godoc
+//
+//	@Summary		Export feed locations as a spreadsheet
+//	@Description	Takes the filters of /feeds/areas and streams every matching location, contact fields are masked. Every export is recorded in the export log.
+//	@Tags			Feed
+//	@Produce		text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Param			format		query	string	false	"csv (default) or xlsx"
+//	@Param			sw_lat		query	number	false	"Sw Lat"
+//	@Param			sw_lng		query	number	false	"Sw Lng"
+//	@Param			ne_lat		query	number	false	"Ne Lat"
+//	@Param			ne_lng		query	number	false	"Ne Lng"
+//	@Param			time_stamp	query	integer	false	"Timestamp"
+//	@Param			reason		query	string	false	"Reason"
+//	@Param			channel		query	string	false	"Channel"
+//	@Param			is_resolved	query	boolean	false	"Only resolved or only unresolved feeds"
+//	@Param			stale		query	string	false	"include (default), exclude or only stale locations"
+//	@Success		200
+//	@Security		ApiKeyAuth
+//	@Router			/feeds/export [GET]
+func GetFeedExport(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		format := ctx.Query("format", feeds.ExportFormatCSV)
+		if format != feeds.ExportFormatCSV && format != feeds.ExportFormatXLSX {
+			return fiber.NewError(fiber.StatusBadRequest, "format must be csv or xlsx")
+		}
+
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
+		}
+
+		logID, err := repo.CreateExportLog(ctx.UserContext(), auth.Actor(ctx), format, string(ctx.Request().URI().QueryString()))
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		filename := fmt.Sprintf("feeds-%s.%s", time.Now().UTC().Format("20060102-150405"), format)
+		ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+`"`)
+
+		if format == feeds.ExportFormatXLSX {
+			ctx.Set(fiber.HeaderContentType, feeds.MIMEXLSX)
+		} else {
+			ctx.Set(fiber.HeaderContentType, feeds.MIMETextCSV)
+		}
+
+		ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			queryCtx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+			defer cancel()
+
+			var (
+				written int
+				err     error
+			)
+			if format == feeds.ExportFormatXLSX {
+				written, err = writeXLSXExport(queryCtx, w, repo, getLocationsQuery)
+			} else {
+				written, err = writeCSVExport(queryCtx, w, repo, getLocationsQuery)
+			}
+			if err == nil {
+				err = w.Flush()
+			}
+			if err != nil {
+				cancel()
+				log.Logger().Info("location export stopped", zap.Int64("exportID", logID), zap.Int("written", written), zap.Error(err))
+			}
+
+			completed := err == nil
+			if err := repo.CompleteExportLog(context.Background(), logID, written, completed); err != nil {
+				log.Logger().Error("could not complete export log", zap.Int64("exportID", logID), zap.Error(err))
+			}
+		})
+
+		return nil
+	}
+}
+
+// writeCSVExport writes the export rows as csv. The byte order mark makes spreadsheet programs read it as utf-8.
+func writeCSVExport(ctx context.Context, w *bufio.Writer, repo *repository.Repository, getLocationsQuery *repository.GetLocationsQuery) (int, error) {
+	if _, err := w.WriteString("\ufeff"); err != nil {
+		return 0, err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(feeds.ExportHeader); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	err := repo.ExportLocations(ctx, getLocationsQuery, func(row feeds.ExportRow) error {
+		if err := cw.Write(row.Record()); err != nil {
+			return err
+		}
+
+		written++
+		if written%streamFlushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			return w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	cw.Flush()
+	return written, cw.Error()
+}
+
+func writeXLSXExport(ctx context.Context, w *bufio.Writer, repo *repository.Repository, getLocationsQuery *repository.GetLocationsQuery) (int, error) {
+	xw, err := xlsx.NewWriter(w, "feeds")
+	if err != nil {
+		return 0, err
+	}
+
+	header := make([]interface{}, 0, len(feeds.ExportHeader))
+	for _, column := range feeds.ExportHeader {
+		header = append(header, column)
+	}
+	if err := xw.WriteRow(header...); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	err = repo.ExportLocations(ctx, getLocationsQuery, func(row feeds.ExportRow) error {
+		if err := xw.WriteRow(row.Values()...); err != nil {
+			return err
+		}
+
+		written++
+		if written%streamFlushEvery == 0 {
+			return w.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return written, err
+	}
+
+	return written, xw.Close()
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/pkg/heatmap"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultHeatmapHalfLife = 72 * time.Hour
+)
+
+// GetFeedHeatmap godoc
+//
+//	@Summary	Get need density over a bounding box
+//	@Tags		Feed
+//	@Produce	json,png
+//	@Success	200			{object}	heatmap.Grid
+//	@Param		sw_lat		query		number	true	"Sw Lat"
+//	@Param		sw_lng		query		number	true	"Sw Lng"
+//	@Param		ne_lat		query		number	true	"Ne Lat"
+//	@Param		ne_lng		query		number	true	"Ne Lng"
+//	@Param		cols		query		integer	false	"Grid columns, max 512"
+//	@Param		rows		query		integer	false	"Grid rows, max 512"
+//	@Param		half_life_h	query		number	false	"Hours after which a location weighs half, 72 by default"
+//	@Param		weights		query		string	false	"Reason weights like enkaz:5,giyim:0.5"
+//	@Param		time_stamp	query		integer	false	"Timestamp"
+//	@Param		reason		query		string	false	"Reason"
+//	@Param		channel		query		string	false	"Channel"
+//	@Param		format		query		string	false	"png for a raster, one pixel per cell"
+//	@Router		/feeds/heatmap [GET]
+func GetFeedHeatmap(repo *repository.Repository) fiber.Handler {
+	// HEATMAP_REASON_WEIGHTS overrides the default weights for every request
+	baseWeights, err := heatmap.ParseWeights(os.Getenv("HEATMAP_REASON_WEIGHTS"), heatmap.DefaultWeights)
+	if err != nil {
+		log.Logger().Error("invalid HEATMAP_REASON_WEIGHTS, using defaults", zap.Error(err))
+		baseWeights = heatmap.DefaultWeights
+	}
+
+	return func(ctx *fiber.Ctx) error {
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
+		}
+
+		spec := heatmap.Spec{
+			SwLat: getLocationsQuery.SwLat,
+			SwLng: getLocationsQuery.SwLng,
+			NeLat: getLocationsQuery.NeLat,
+			NeLng: getLocationsQuery.NeLng,
+			Cols:  ctx.QueryInt("cols", heatmap.DefaultCells),
+			Rows:  ctx.QueryInt("rows", heatmap.DefaultCells),
+		}
+		if !spec.Valid() {
+			return fiber.NewError(fiber.StatusBadRequest, "a bounding box and up to 512 cols and rows are required")
+		}
+
+		halfLife := defaultHeatmapHalfLife
+		if halfLifeStr := ctx.Query("half_life_h"); halfLifeStr != "" {
+			hours, err := strconv.ParseFloat(halfLifeStr, 64)
+			if err != nil || hours <= 0 {
+				return ctx.SendStatus(fiber.StatusBadRequest)
+			}
+			halfLife = time.Duration(hours * float64(time.Hour))
+		}
+
+		weights, err := heatmap.ParseWeights(ctx.Query("weights"), baseWeights)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+
+		// the grid is the spatial filter, cells are matched by point and paging does not apply
+		getLocationsQuery.SwLat, getLocationsQuery.SwLng = 0, 0
+		getLocationsQuery.NeLat, getLocationsQuery.NeLng = 0, 0
+		getLocationsQuery.Limit, getLocationsQuery.Cursor = 0, nil
+
+		grid, err := repo.GetDensityGrid(ctx.UserContext(), getLocationsQuery, spec, weights, halfLife)
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		if ctx.Query("format") == "png" {
+			var buf bytes.Buffer
+			if err := grid.WritePNG(&buf); err != nil {
+				return err
+			}
+
+			ctx.Set(fiber.HeaderContentType, "image/png")
+			return ctx.Send(buf.Bytes())
+		}
+
+		return ctx.JSON(grid)
+	}
+}
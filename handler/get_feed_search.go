@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/reader"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetFeedSearch godoc
+//
+//	@Summary	Full text search over feed texts
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200			{object}	feeds.SearchResponse
+//	@Param		q			query		string	true	"Search text, quoted phrases and -word are supported"
+//	@Param		sw_lat		query		number	false	"Sw Lat"
+//	@Param		sw_lng		query		number	false	"Sw Lng"
+//	@Param		ne_lat		query		number	false	"Ne Lat"
+//	@Param		ne_lng		query		number	false	"Ne Lng"
+//	@Param		time_stamp	query		integer	false	"Timestamp"
+//	@Param		reason		query		string	false	"Reason"
+//	@Param		channel		query		string	false	"Channel"
+//	@Param		lat			query		number	false	"Radius filter center latitude"
+//	@Param		lng			query		number	false	"Radius filter center longitude"
+//	@Param		radius_m	query		number	false	"Radius filter in meters, max 100000"
+//	@Param		polygon		query		string	false	"GeoJSON Polygon geometry"
+//	@Param		limit		query		integer	false	"Result size, 100 by default, max 1000"
+//	@Param		backend		query		string	false	"postgres or elastic, elastic by default and falls back to postgres on errors"
+//	@Router		/feeds/search [GET]
+func GetFeedSearch(locationReader *reader.Router) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		text := strings.TrimSpace(ctx.Query("q"))
+		if text == "" {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
+		}
+
+		// results are ranked by relevance, there is no next page
+		if getLocationsQuery.Cursor != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "search results are not cursor paginated")
+		}
+
+		if getLocationsQuery.Limit > feeds.MaxSearchSize {
+			getLocationsQuery.Limit = feeds.MaxSearchSize
+		}
+
+		backend, err := reader.ParseBackend(ctx.Query("backend"))
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		data, servedBy, err := locationReader.SearchLocations(ctx.UserContext(), backend, getLocationsQuery, text)
+		ctx.Set(reader.HeaderName, string(servedBy))
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(&feeds.SearchResponse{
+			Count:   len(data),
+			Results: data,
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/pkg/mvt"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	tileLayerName = "feeds"
+
+	// points closer than this fraction of a tile to its edge are drawn on the neighbour tile too,
+	// otherwise symbols are clipped at tile borders
+	tileBuffer = 1.0 / 64
+)
+
+// GetTile godoc
+//
+//	@Summary		Get feed locations as a Mapbox Vector Tile
+//	@Description	A tile holds the newest 20000 locations, /feeds/clusters covers every location at low zoom levels.
+//	@Tags			Tile
+//	@Produce		application/vnd.mapbox-vector-tile
+//	@Success		200			{string}	binary
+//	@Param			z			path		integer	true	"Zoom"
+//	@Param			x			path		integer	true	"Tile column"
+//	@Param			y			path		integer	true	"Tile row"
+//	@Param			time_stamp	query		integer	false	"Timestamp"
+//	@Param			reason		query		string	false	"Reason"
+//	@Param			channel		query		string	false	"Channel"
+//	@Router			/tiles/{z}/{x}/{y}.mvt [GET]
+func GetTile(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		z, errZ := strconv.Atoi(ctx.Params("z"))
+		x, errX := strconv.Atoi(ctx.Params("x"))
+		y, errY := strconv.Atoi(ctx.Params("y"))
+		if errZ != nil || errX != nil || errY != nil || !mvt.ValidTile(z, x, y) {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		getLocationsQuery, err := parseLocationsQuery(ctx)
+		if err != nil {
+			return err
+		}
+
+		// the tile is the spatial filter, paging does not apply
+		getLocationsQuery.SwLat, getLocationsQuery.SwLng = 0, 0
+		getLocationsQuery.NeLat, getLocationsQuery.NeLng = 0, 0
+		getLocationsQuery.Limit, getLocationsQuery.Cursor = 0, nil
+
+		data, err := repo.GetTileLocations(ctx.UserContext(), getLocationsQuery, mvt.TileBounds(z, x, y, tileBuffer))
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		layer := mvt.NewLayer(tileLayerName, z, x, y)
+		for _, location := range data {
+			properties := map[string]interface{}{
+				"entry_id":             location.EntryID,
+				"epoch":                location.Epoch,
+				"is_location_verified": location.IsLocationVerified,
+				"is_need_verified":     location.IsNeedVerified,
+			}
+			if location.Reason != nil {
+				properties["reason"] = *location.Reason
+			}
+			if location.Channel != nil {
+				properties["channel"] = *location.Channel
+			}
+
+			layer.Add(mvt.Feature{
+				ID:         uint64(location.ID),
+				Lat:        location.Latitude,
+				Lng:        location.Longitude,
+				Properties: properties,
+			})
+		}
+
+		ctx.Set(fiber.HeaderContentType, mvt.ContentType)
+		return ctx.Send(mvt.Marshal(layer))
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetLocationHistoryHandler godoc
+//
+//	@Summary	Get the location corrections of a feed
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200	{object}	feeds.LocationHistoryResponse
+//	@Param		id	path		integer	true	"Feed Id"
+//	@Router		/feeds/{id}/history [GET]
+func GetLocationHistoryHandler(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		data, err := repo.GetLocationHistory(ctx.UserContext(), feedID)
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(&feeds.LocationHistoryResponse{
+			Count:   len(data),
+			Results: data,
+		})
+	}
+}
+
+// RollbackLocationHandler godoc
+//
+//	@Summary	Restore a feed location to what it was before a correction
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200				{object}	feeds.LocationCorrection
+//	@Param		id				path		integer	true	"Feed Id"
+//	@Param		correctionId	path		integer	true	"Id of the correction to roll back"
+//	@Param		X-Actor			header		string	false	"Who rolled the location back"
+//	@Security	ApiKeyAuth
+//	@Router		/feeds/{id}/history/{correctionId}/rollback [POST]
+func RollbackLocationHandler(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		correctionID, err := strconv.ParseInt(ctx.Params("correctionId"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		correction, err := repo.RollbackLocation(ctx.UserContext(), feedID, correctionID, auth.Actor(ctx))
+		if errors.Is(err, repository.ErrCorrectionNotFound) {
+			return ctx.SendStatus(fiber.StatusNotFound)
+		}
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(correction)
+	}
+}
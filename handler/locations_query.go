@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseLocationsQuery reads the feeds_location filters shared by the location endpoints.
+// It only fails on parameters that can not be ignored, like a malformed cursor.
+func parseLocationsQuery(ctx *fiber.Ctx) (*repository.GetLocationsQuery, error) {
+	swLatStr := ctx.Query("sw_lat")
+	swLngStr := ctx.Query("sw_lng")
+	neLatStr := ctx.Query("ne_lat")
+	neLngStr := ctx.Query("ne_lng")
+	timeStampStr := ctx.Query("time_stamp")
+	reason := ctx.Query("reason", "")
+	channel := ctx.Query("channel", "")
+	extraParams := ctx.Query("extraParams", "")
+	isLocationVerified := ctx.Query("is_location_verified", "")
+	isNeedVerified := ctx.Query("is_need_verified", "")
+	isResolved := ctx.Query("is_resolved", "")
+	excludeSatisfied, _ := strconv.ParseBool(ctx.Query("exclude_satisfied", ""))
+	stale := ctx.Query("stale", "")
+	limitStr := ctx.Query("limit", "")
+	cursorStr := ctx.Query("cursor", "")
+	radiusStr := ctx.Query("radius_m", "")
+	polygonStr := ctx.Query("polygon", "")
+	sort := ctx.Query("sort", "")
+
+	var timestamp int64
+	if timeStampStr == "" {
+		timestamp = time.Now().AddDate(-1, -1, -1).Unix()
+	} else {
+		timeInt, err := strconv.ParseInt(timeStampStr, 10, 64)
+		if err != nil {
+			timestamp = time.Now().AddDate(-1, -1, -1).Unix()
+		} else {
+			timestamp = timeInt
+		}
+	}
+
+	swLat, _ := strconv.ParseFloat(swLatStr, 64)
+	swLng, _ := strconv.ParseFloat(swLngStr, 64)
+	neLat, _ := strconv.ParseFloat(neLatStr, 64)
+	neLng, _ := strconv.ParseFloat(neLngStr, 64)
+
+	extraParamsBool, _ := strconv.ParseBool(extraParams)
+
+	var limit int
+	if limitStr != "" {
+		limitInt, err := strconv.Atoi(limitStr)
+		if err != nil || limitInt <= 0 {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "limit must be a positive integer")
+		}
+		limit = limitInt
+	}
+
+	var cursor *feeds.Cursor
+	if cursorStr != "" {
+		c, err := feeds.DecodeCursor(cursorStr)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		cursor = c
+
+		if limit == 0 {
+			limit = feeds.DefaultPageSize
+		}
+	}
+
+	if limit > feeds.MaxPageSize {
+		limit = feeds.MaxPageSize
+	}
+
+	var lat, lng, radius float64
+	if radiusStr != "" {
+		var errLat, errLng, errRadius error
+		lat, errLat = strconv.ParseFloat(ctx.Query("lat"), 64)
+		lng, errLng = strconv.ParseFloat(ctx.Query("lng"), 64)
+		radius, errRadius = strconv.ParseFloat(radiusStr, 64)
+		if errLat != nil || errLng != nil || errRadius != nil ||
+			lat < -90 || lat > 90 || lng < -180 || lng > 180 ||
+			radius <= 0 || radius > feeds.MaxRadiusM {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "radius filter needs lat, lng and a radius_m up to 100000")
+		}
+	}
+
+	var polygon [][]float64
+	if polygonStr != "" {
+		ring, err := feeds.ParsePolygon(polygonStr)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		polygon = ring
+	}
+
+	if !feeds.ValidStaleFilter(stale) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, feeds.ErrInvalidStaleFilter.Error())
+	}
+
+	sortByDistance := sort == "distance"
+	if sortByDistance && (radius == 0 || cursor != nil) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "sort=distance needs a radius filter and can not be combined with a cursor")
+	}
+
+	return &repository.GetLocationsQuery{
+		SwLat:              swLat,
+		SwLng:              swLng,
+		NeLat:              neLat,
+		NeLng:              neLng,
+		Timestamp:          timestamp,
+		Reason:             reason,
+		Channel:            channel,
+		ExtraParams:        extraParamsBool,
+		IsLocationVerified: isLocationVerified,
+		IsNeedVerified:     isNeedVerified,
+		IsResolved:         isResolved,
+		ExcludeSatisfied:   excludeSatisfied,
+		Stale:              stale,
+		Limit:              limit,
+		Cursor:             cursor,
+		Lat:                lat,
+		Lng:                lng,
+		RadiusM:            radius,
+		Polygon:            polygon,
+		SortByDistance:     sortByDistance,
+	}, nil
+}
@@ -1,37 +1,79 @@
 package handler
 
 import (
+	"errors"
+	"os"
 	"strconv"
+	"time"
 
+	"github.com/acikkaynak/backend-api-go/matching"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
 	"github.com/acikkaynak/backend-api-go/needs"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
 	"github.com/acikkaynak/backend-api-go/repository"
 	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
+const defaultDuplicateWindow = 48 * time.Hour
+
 type NeedsHandler struct {
-	repo *repository.Repository
+	repo    *repository.Repository
+	matcher *matching.Service
+	// duplicateWindow is how far back needs at the same address are checked for duplicates
+	duplicateWindow time.Duration
 }
 
-func NewNeedsHandler(repo *repository.Repository) *NeedsHandler {
-	return &NeedsHandler{repo: repo}
+// NewNeedsHandler reads NEED_DUPLICATE_WINDOW (a duration, 48h by default).
+func NewNeedsHandler(repo *repository.Repository, matcher *matching.Service) *NeedsHandler {
+	duplicateWindow := defaultDuplicateWindow
+	if env := os.Getenv("NEED_DUPLICATE_WINDOW"); env != "" {
+		w, err := time.ParseDuration(env)
+		if err != nil || w <= 0 {
+			log.Logger().Error("invalid NEED_DUPLICATE_WINDOW, using the default", zap.String("value", env))
+		} else {
+			duplicateWindow = w
+		}
+	}
+
+	return &NeedsHandler{repo: repo, matcher: matcher, duplicateWindow: duplicateWindow}
 }
 
 // HandleCreate godoc
 //
-//	@Summary	Create Need
-//	@Tags		Need
-//	@Produce	json
-//	@Success	200		{object}	needs.LiteNeed
-//	@Param		body	body		needs.CreateNeedRequest	true	"RequestBody"
-//	@Security	ApiKeyAuth
-//	@Router		/needs [POST]
+//	@Summary		Create Need
+//	@Description	Categories have to be out of /reasons, a quantity and unit like 50 battaniye are optional.
+//	@Description	A need likely repeating a recent one at the same address is not created, the duplicates are returned
+//	@Description	with 409 and sending the request again with confirm=true creates it anyway.
+//	@Tags			Need
+//	@Produce		json
+//	@Success		200		{object}	needs.LiteNeed
+//	@Failure		409		{object}	needs.DuplicatesResponse
+//	@Param			body	body		needs.CreateNeedRequest	true	"RequestBody"
+//	@Param			X-Actor	header		string					false	"Who created the need"
+//	@Security		ApiKeyAuth
+//	@Router			/needs [POST]
 func (h *NeedsHandler) HandleCreate(ctx *fiber.Ctx) error {
 	req := needs.CreateNeedRequest{}
 	if err := ctx.BodyParser(&req); err != nil {
 		return ctx.JSON(err)
 	}
 
-	id, err := h.repo.CreateNeed(req.Address, req.Description)
+	if err := req.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	if !req.Confirm {
+		duplicates := h.findDuplicates(ctx, req)
+		if len(duplicates) > 0 {
+			return ctx.Status(fiber.StatusConflict).JSON(&needs.DuplicatesResponse{
+				ConfirmRequired: true,
+				Duplicates:      duplicates,
+			})
+		}
+	}
+
+	id, err := h.repo.CreateNeed(ctx.UserContext(), req, auth.Actor(ctx))
 	if err != nil {
 		return ctx.JSON(err)
 	}
@@ -45,24 +87,269 @@ func (h *NeedsHandler) HandleCreate(ctx *fiber.Ctx) error {
 
 // HandleList godoc
 //
-//	@Summary	Get Needs
+//	@Summary		Get Needs
+//	@Description	Lists needs newest first. With limit or cursor a page holds limit needs and next_cursor points at the next one.
+//	@Tags			Need
+//	@Produce		json
+//	@Success		200					{object}	needs.Response
+//	@Param			only_not_resolved	query		bool	false	"Is Only Not Resolved"
+//	@Param			sw_lat				query		number	false	"Southwest latitude of the bounding box"
+//	@Param			sw_lng				query		number	false	"Southwest longitude of the bounding box"
+//	@Param			ne_lat				query		number	false	"Northeast latitude of the bounding box"
+//	@Param			ne_lng				query		number	false	"Northeast longitude of the bounding box"
+//	@Param			lat					query		number	false	"Latitude of the radius filter center"
+//	@Param			lng					query		number	false	"Longitude of the radius filter center"
+//	@Param			radius_m			query		number	false	"Radius in meters, up to 100000"
+//	@Param			from				query		integer	false	"Needs created at or after this unix timestamp"
+//	@Param			to					query		integer	false	"Needs created before this unix timestamp"
+//	@Param			q					query		string	false	"Text searched in the description"
+//	@Param			category			query		string	false	"Comma separated categories out of /reasons, needs asking for any of them"
+//	@Param			sort				query		string	false	"newest, oldest or distance (needs radius_m)"
+//	@Param			limit				query		integer	false	"Page size, max 10000, 1000 when only a cursor is given"
+//	@Param			cursor				query		string	false	"Cursor returned as next_cursor by the previous page"
+//	@Router			/needs [GET]
+func (h *NeedsHandler) HandleList(ctx *fiber.Ctx) error {
+	q, err := parseNeedsQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.repo.GetNeeds(ctx.UserContext(), q)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	total, err := h.repo.CountNeeds(ctx.UserContext(), q)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	limit := q.Limit
+	if q.Sort == needs.SortDistance {
+		limit = 0
+	}
+
+	return ctx.JSON(needs.NewResponse(data, limit, total))
+}
+
+// HandleGet godoc
+//
+//	@Summary	Get Need
 //	@Tags		Need
 //	@Produce	json
-//	@Success	200					{object}	needs.Response
-//	@Param		only_not_resolved	query		bool	true	"Is Only Not Resolved"
-//	@Router		/needs [GET]
-func (h *NeedsHandler) HandleList(ctx *fiber.Ctx) error {
-	onlyNotResolvedStr := ctx.Query("only_not_resolved")
-	onlyNotResolved, _ := strconv.ParseBool(onlyNotResolvedStr)
-	data, err := h.repo.GetNeeds(onlyNotResolved)
+//	@Success	200	{object}	needs.Need
+//	@Param		id	path		integer	true	"Need Id"
+//	@Router		/needs/{id} [GET]
+func (h *NeedsHandler) HandleGet(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	need, err := h.repo.GetNeed(ctx.UserContext(), id)
+	if errors.Is(err, repository.ErrNeedNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
 	if err != nil {
 		return ctx.JSON(err)
 	}
 
-	resp := &needs.Response{
-		Count:   len(data),
-		Results: data,
+	return ctx.JSON(need)
+}
+
+// HandleUpdate godoc
+//
+//	@Summary		Edit Need
+//	@Description	Updates the description, address or extra parameters of a need, a new address is geocoded again.
+//	@Tags			Need
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	needs.Need
+//	@Param			id		path		integer					true	"Need Id"
+//	@Param			body	body		needs.UpdateNeedRequest	true	"Fields to change"
+//	@Param			X-Actor	header		string					false	"Who changed the need"
+//	@Security		ApiKeyAuth
+//	@Router			/needs/{id} [PATCH]
+func (h *NeedsHandler) HandleUpdate(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
 	}
 
-	return ctx.JSON(resp)
+	req := needs.UpdateNeedRequest{}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if err := req.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	err = h.repo.UpdateNeed(ctx.UserContext(), id, req, auth.Actor(ctx))
+	return h.sendNeed(ctx, id, err)
+}
+
+// HandleResolve godoc
+//
+//	@Summary	Mark a need as resolved
+//	@Tags		Need
+//	@Produce	json
+//	@Success	200		{object}	needs.Need
+//	@Param		id		path		integer	true	"Need Id"
+//	@Param		X-Actor	header		string	false	"Who resolved the need"
+//	@Security	ApiKeyAuth
+//	@Router		/needs/{id}/resolve [POST]
+func (h *NeedsHandler) HandleResolve(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	err = h.repo.ResolveNeed(ctx.UserContext(), id, auth.Actor(ctx))
+	return h.sendNeed(ctx, id, err)
+}
+
+// HandleDelete godoc
+//
+//	@Summary	Delete Need
+//	@Tags		Need
+//	@Success	204
+//	@Param		id		path	integer	true	"Need Id"
+//	@Param		X-Actor	header	string	false	"Who deleted the need"
+//	@Security	ApiKeyAuth
+//	@Router		/needs/{id} [DELETE]
+func (h *NeedsHandler) HandleDelete(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	err = h.repo.DeleteNeed(ctx.UserContext(), id, auth.Actor(ctx))
+	if errors.Is(err, repository.ErrNeedNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// HandleHistory godoc
+//
+//	@Summary	Get the recorded changes of a need
+//	@Tags		Need
+//	@Produce	json
+//	@Success	200	{object}	needs.HistoryResponse
+//	@Param		id	path		integer	true	"Need Id"
+//	@Router		/needs/{id}/history [GET]
+func (h *NeedsHandler) HandleHistory(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	changes, err := h.repo.GetNeedChanges(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(&needs.HistoryResponse{
+		Count:   len(changes),
+		Results: changes,
+	})
+}
+
+// HandleMatches godoc
+//
+//	@Summary		Propose supply offers for a need
+//	@Description	Lists the nearest active offers of the need's categories, the need has to be geocoded.
+//	@Tags			Need
+//	@Produce		json
+//	@Success		200		{object}	matching.Response
+//	@Param			id		path		integer	true	"Need Id"
+//	@Param			limit	query		integer	false	"Number of matches, 20 by default, max 100"
+//	@Router			/needs/{id}/matches [GET]
+func (h *NeedsHandler) HandleMatches(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	limit, err := parseMatchLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := h.matcher.ForNeed(ctx.UserContext(), id, limit)
+	if errors.Is(err, repository.ErrNeedNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if errors.Is(err, matching.ErrNotLocated) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(&matching.Response{Count: len(matches), Results: matches})
+}
+
+// HandleMerge godoc
+//
+//	@Summary		Merge duplicate needs into a need
+//	@Description	The duplicates are deleted, their categories are combined into the need and its history includes theirs.
+//	@Tags			Need
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	needs.Need
+//	@Param			id		path		integer					true	"Need Id"
+//	@Param			body	body		needs.MergeNeedsRequest	true	"Needs to merge"
+//	@Param			X-Actor	header		string					false	"Who merged the needs"
+//	@Security		ApiKeyAuth
+//	@Router			/needs/{id}/merge [POST]
+func (h *NeedsHandler) HandleMerge(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	req := needs.MergeNeedsRequest{}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if err := req.Validate(id); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	err = h.repo.MergeNeeds(ctx.UserContext(), id, req.DuplicateIDs, auth.Actor(ctx))
+	return h.sendNeed(ctx, id, err)
+}
+
+// findDuplicates returns the recent needs at the same address the new need likely repeats. A failed
+// lookup is logged and does not keep the need from being created.
+func (h *NeedsHandler) findDuplicates(ctx *fiber.Ctx, req needs.CreateNeedRequest) []needs.Duplicate {
+	candidates, err := h.repo.GetNeedsByAddress(ctx.UserContext(), needs.NormalizeAddress(req.Address), time.Now().Add(-h.duplicateWindow))
+	if err != nil {
+		log.Logger().Error("could not look for duplicate needs", zap.Error(err))
+		return nil
+	}
+
+	return needs.FindDuplicates(req.Description, candidates)
+}
+
+// sendNeed responds with the need after a change to it.
+func (h *NeedsHandler) sendNeed(ctx *fiber.Ctx, id int64, err error) error {
+	if errors.Is(err, repository.ErrNeedNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	need, err := h.repo.GetNeed(ctx.UserContext(), id)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(need)
 }
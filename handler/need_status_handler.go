@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// UpdateNeedStatusHandler godoc
+//
+//	@Summary		Mark a need of a feed as open or delivered
+//	@Description	Responds 503 when the status could not reach search, retrying the same request completes it.
+//	@Tags			Feed
+//	@Accept			json
+//	@Produce		json
+//	@Success		200						{object}	feeds.NeedHistoryResponse
+//	@Param			id						path		integer							true	"Feed Id"
+//	@Param			label					path		string							true	"Need label"
+//	@Param			UpdateNeedStatusRequest	body		feeds.UpdateNeedStatusRequest	true	"false once the need is delivered"
+//	@Param			X-Actor					header		string							false	"Who updated the need"
+//	@Security		ApiKeyAuth
+//	@Router			/feeds/{id}/needs/{label} [PATCH]
+func UpdateNeedStatusHandler(repo *repository.Repository, index *search.LocationIndex) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		label, err := url.PathUnescape(ctx.Params("label"))
+		if err != nil || label == "" {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		var req feeds.UpdateNeedStatusRequest
+		if err := ctx.BodyParser(&req); err != nil {
+			return fmt.Errorf("failed to decode request. err: %w", err)
+		}
+		if req.Status == nil {
+			return fiber.NewError(fiber.StatusBadRequest, "status is required")
+		}
+
+		changes, err := repo.SetNeedStatus(ctx.UserContext(), feedID, label, *req.Status, auth.Actor(ctx))
+		if errors.Is(err, repository.ErrNeedNotFound) {
+			return ctx.SendStatus(fiber.StatusNotFound)
+		}
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		// the stored status is sent again on retry, so a failed elastic update fails the request
+		if err := index.SetNeedStatus(ctx.UserContext(), feedID, changes[0].Label, *req.Status); err != nil {
+			log.Logger().Error("could not sync need status to elastic", zap.Int64("feedID", feedID), zap.Error(err))
+			return fiber.NewError(fiber.StatusServiceUnavailable, "need status is stored but not searchable yet, retry the request")
+		}
+
+		return ctx.JSON(&feeds.NeedHistoryResponse{
+			Count:   len(changes),
+			Results: changes,
+		})
+	}
+}
+
+// GetNeedHistoryHandler godoc
+//
+//	@Summary	Get the need status changes of a feed
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200	{object}	feeds.NeedHistoryResponse
+//	@Param		id	path		integer	true	"Feed Id"
+//	@Router		/feeds/{id}/needs/history [GET]
+func GetNeedHistoryHandler(repo *repository.Repository) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		data, err := repo.GetNeedHistory(ctx.UserContext(), feedID)
+		if err != nil {
+			return ctx.JSON(err)
+		}
+
+		return ctx.JSON(&feeds.NeedHistoryResponse{
+			Count:   len(data),
+			Results: data,
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseNeedsQuery reads the filters of the needs list, the bounding box and radius parameters are
+// named like the location endpoints'. from and to are unix timestamps.
+func parseNeedsQuery(ctx *fiber.Ctx) (*repository.GetNeedsQuery, error) {
+	onlyNotResolved, _ := strconv.ParseBool(ctx.Query("only_not_resolved"))
+	swLat, _ := strconv.ParseFloat(ctx.Query("sw_lat"), 64)
+	swLng, _ := strconv.ParseFloat(ctx.Query("sw_lng"), 64)
+	neLat, _ := strconv.ParseFloat(ctx.Query("ne_lat"), 64)
+	neLng, _ := strconv.ParseFloat(ctx.Query("ne_lng"), 64)
+	sort := ctx.Query("sort")
+
+	var from, to time.Time
+	for _, param := range []struct {
+		name string
+		dest *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		if value := ctx.Query(param.name); value != "" {
+			unix, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fiber.NewError(fiber.StatusBadRequest, param.name+" must be a unix timestamp")
+			}
+			*param.dest = time.Unix(unix, 0)
+		}
+	}
+
+	// paging is opt-in, without limit and cursor every matching need is returned
+	var limit int
+	if limitStr := ctx.Query("limit"); limitStr != "" {
+		limitInt, err := strconv.Atoi(limitStr)
+		if err != nil || limitInt <= 0 {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "limit must be a positive integer")
+		}
+		limit = limitInt
+	}
+
+	var cursor *needs.Cursor
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		c, err := needs.DecodeCursor(cursorStr)
+		if err != nil {
+			return nil, fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		cursor = c
+
+		if limit == 0 {
+			limit = feeds.DefaultPageSize
+		}
+	}
+	if limit > feeds.MaxPageSize {
+		limit = feeds.MaxPageSize
+	}
+
+	var lat, lng, radius float64
+	if radiusStr := ctx.Query("radius_m"); radiusStr != "" {
+		var errLat, errLng, errRadius error
+		lat, errLat = strconv.ParseFloat(ctx.Query("lat"), 64)
+		lng, errLng = strconv.ParseFloat(ctx.Query("lng"), 64)
+		radius, errRadius = strconv.ParseFloat(radiusStr, 64)
+		if errLat != nil || errLng != nil || errRadius != nil ||
+			lat < -90 || lat > 90 || lng < -180 || lng > 180 ||
+			radius <= 0 || radius > feeds.MaxRadiusM {
+			return nil, fiber.NewError(fiber.StatusBadRequest, "radius filter needs lat, lng and a radius_m up to 100000")
+		}
+	}
+
+	categories, err := parseCategoryQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !needs.ValidSort(sort) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "sort must be newest, oldest or distance")
+	}
+	if sort == needs.SortDistance && (radius == 0 || cursor != nil) {
+		return nil, fiber.NewError(fiber.StatusBadRequest, "sort=distance needs a radius filter and can not be combined with a cursor")
+	}
+
+	return &repository.GetNeedsQuery{
+		SwLat:           swLat,
+		SwLng:           swLng,
+		NeLat:           neLat,
+		NeLng:           neLng,
+		Lat:             lat,
+		Lng:             lng,
+		RadiusM:         radius,
+		From:            from,
+		To:              to,
+		Text:            strings.TrimSpace(ctx.Query("q")),
+		Categories:      categories,
+		OnlyNotResolved: onlyNotResolved,
+		Sort:            sort,
+		Limit:           limit,
+		Cursor:          cursor,
+	}, nil
+}
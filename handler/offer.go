@@ -0,0 +1,208 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/matching"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/acikkaynak/backend-api-go/offers"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+)
+
+type OffersHandler struct {
+	repo    *repository.Repository
+	matcher *matching.Service
+}
+
+func NewOffersHandler(repo *repository.Repository, matcher *matching.Service) *OffersHandler {
+	return &OffersHandler{repo: repo, matcher: matcher}
+}
+
+// HandleCreate godoc
+//
+//	@Summary		Register a supply offer
+//	@Description	Categories have to be out of /reasons, a quantity and unit like 2000 battaniye are optional.
+//	@Tags			Offer
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	offers.Offer
+//	@Param			body	body		offers.CreateOfferRequest	true	"RequestBody"
+//	@Param			X-Actor	header		string						false	"Who registered the offer"
+//	@Security		ApiKeyAuth
+//	@Router			/offers [POST]
+func (h *OffersHandler) HandleCreate(ctx *fiber.Ctx) error {
+	req := offers.CreateOfferRequest{}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if err := req.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	id, err := h.repo.CreateOffer(ctx.UserContext(), req, auth.Actor(ctx))
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return h.sendOffer(ctx, id, nil)
+}
+
+// HandleList godoc
+//
+//	@Summary	List supply offers
+//	@Tags		Offer
+//	@Produce	json
+//	@Success	200			{object}	offers.Response
+//	@Param		category	query		string	false	"Comma separated categories out of /reasons, offers of any of them"
+//	@Param		only_active	query		bool	false	"Leave out offers which are not available anymore"
+//	@Param		limit		query		integer	false	"Page size, 1000 by default, max 10000"
+//	@Param		cursor		query		string	false	"Cursor returned as next_cursor by the previous page"
+//	@Router		/offers [GET]
+func (h *OffersHandler) HandleList(ctx *fiber.Ctx) error {
+	onlyActive, _ := strconv.ParseBool(ctx.Query("only_active"))
+
+	categories, err := parseCategoryQuery(ctx)
+	if err != nil {
+		return err
+	}
+
+	limit := ctx.QueryInt("limit", feeds.DefaultPageSize)
+	if limit <= 0 {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if limit > feeds.MaxPageSize {
+		limit = feeds.MaxPageSize
+	}
+
+	var cursor *needs.Cursor
+	if cursorStr := ctx.Query("cursor"); cursorStr != "" {
+		c, err := needs.DecodeCursor(cursorStr)
+		if err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, err.Error())
+		}
+		cursor = c
+	}
+
+	data, err := h.repo.GetOffers(ctx.UserContext(), &repository.GetOffersQuery{
+		Categories: categories,
+		OnlyActive: onlyActive,
+		Limit:      limit,
+		Cursor:     cursor,
+	})
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(offers.NewResponse(data, limit))
+}
+
+// HandleGet godoc
+//
+//	@Summary	Get a supply offer
+//	@Tags		Offer
+//	@Produce	json
+//	@Success	200	{object}	offers.Offer
+//	@Param		id	path		integer	true	"Offer Id"
+//	@Router		/offers/{id} [GET]
+func (h *OffersHandler) HandleGet(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	return h.sendOffer(ctx, id, nil)
+}
+
+// HandleUpdate godoc
+//
+//	@Summary		Edit a supply offer
+//	@Description	Fields left out are kept, is_active=false marks an offer as no longer available.
+//	@Tags			Offer
+//	@Accept			json
+//	@Produce		json
+//	@Success		200		{object}	offers.Offer
+//	@Param			id		path		integer						true	"Offer Id"
+//	@Param			body	body		offers.UpdateOfferRequest	true	"Fields to change"
+//	@Param			X-Actor	header		string						false	"Who changed the offer"
+//	@Security		ApiKeyAuth
+//	@Router			/offers/{id} [PATCH]
+func (h *OffersHandler) HandleUpdate(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	req := offers.UpdateOfferRequest{}
+	if err := ctx.BodyParser(&req); err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if err := req.Validate(); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	err = h.repo.UpdateOffer(ctx.UserContext(), id, req, auth.Actor(ctx))
+	return h.sendOffer(ctx, id, err)
+}
+
+// HandleMatches godoc
+//
+//	@Summary		Propose needs and feed locations for a supply offer
+//	@Description	Lists the nearest open needs and feed locations asking for the offer's categories.
+//	@Tags			Offer
+//	@Produce		json
+//	@Success		200		{object}	matching.Response
+//	@Param			id		path		integer	true	"Offer Id"
+//	@Param			limit	query		integer	false	"Number of matches, 20 by default, max 100"
+//	@Router			/offers/{id}/matches [GET]
+func (h *OffersHandler) HandleMatches(ctx *fiber.Ctx) error {
+	id, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	limit, err := parseMatchLimit(ctx)
+	if err != nil {
+		return err
+	}
+
+	matches, err := h.matcher.ForOffer(ctx.UserContext(), id, limit)
+	if errors.Is(err, repository.ErrOfferNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(&matching.Response{Count: len(matches), Results: matches})
+}
+
+// sendOffer responds with the offer after reading or changing it.
+func (h *OffersHandler) sendOffer(ctx *fiber.Ctx, id int64, err error) error {
+	if err == nil {
+		var offer *offers.Offer
+		if offer, err = h.repo.GetOffer(ctx.UserContext(), id); err == nil {
+			return ctx.JSON(offer)
+		}
+	}
+
+	if errors.Is(err, repository.ErrOfferNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	return ctx.JSON(err)
+}
+
+// parseMatchLimit reads the limit of the matches endpoints.
+func parseMatchLimit(ctx *fiber.Ctx) (int, error) {
+	limit := ctx.QueryInt("limit", matching.DefaultLimit)
+	if limit <= 0 {
+		return 0, fiber.NewError(fiber.StatusBadRequest, "limit must be a positive integer")
+	}
+	if limit > matching.MaxLimit {
+		limit = matching.MaxLimit
+	}
+	return limit, nil
+}
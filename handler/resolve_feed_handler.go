@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// ResolveFeedHandler godoc
+//
+//	@Summary	Mark a feed as handled
+//	@Tags		Feed
+//	@Accept		json
+//	@Produce	json
+//	@Success	200					{object}	feeds.Feed
+//	@Param		id					path		integer						true	"Feed Id"
+//	@Param		ResolveFeedRequest	body		feeds.ResolveFeedRequest	false	"Outcome of the request"
+//	@Param		X-Actor				header		string						false	"Who resolved the feed"
+//	@Security	ApiKeyAuth
+//	@Router		/feeds/{id}/resolve [POST]
+func ResolveFeedHandler(repo *repository.Repository, index *search.LocationIndex) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		var req feeds.ResolveFeedRequest
+		if len(ctx.Body()) > 0 {
+			if err := ctx.BodyParser(&req); err != nil {
+				return fmt.Errorf("failed to decode request. err: %w", err)
+			}
+		}
+
+		err = repo.ResolveFeed(ctx.UserContext(), feedID, auth.Actor(ctx), req.Outcome)
+		return sendResolution(ctx, repo, index, feedID, true, err)
+	}
+}
+
+// ReopenFeedHandler godoc
+//
+//	@Summary	Mark a resolved feed as not handled
+//	@Tags		Feed
+//	@Produce	json
+//	@Success	200	{object}	feeds.Feed
+//	@Param		id	path		integer	true	"Feed Id"
+//	@Security	ApiKeyAuth
+//	@Router		/feeds/{id}/reopen [POST]
+func ReopenFeedHandler(repo *repository.Repository, index *search.LocationIndex) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		feedID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+		if err != nil {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+
+		err = repo.ReopenFeed(ctx.UserContext(), feedID)
+		return sendResolution(ctx, repo, index, feedID, false, err)
+	}
+}
+
+// sendResolution syncs a stored resolution change to elastic and responds with the updated feed.
+// A failed elastic update is logged and the stored feed is still returned, resolving or reopening
+// again is idempotent and sends the flag to elastic once more.
+func sendResolution(ctx *fiber.Ctx, repo *repository.Repository, index *search.LocationIndex, feedID int64, isResolved bool, err error) error {
+	if errors.Is(err, repository.ErrFeedNotFound) {
+		return ctx.SendStatus(fiber.StatusNotFound)
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	if err := index.SetResolved(ctx.UserContext(), feedID, isResolved); err != nil {
+		log.Logger().Error("could not sync feed resolution to elastic", zap.Int64("feedID", feedID), zap.Error(err))
+	}
+
+	feed, err := repo.GetFeed(feedID)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(feed)
+}
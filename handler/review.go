@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+const defaultReviewClaimTTL = 15 * time.Minute
+
+type ReviewHandler struct {
+	repo     *repository.Repository
+	index    *search.LocationIndex
+	claimTTL time.Duration
+}
+
+// NewReviewHandler reads REVIEW_CLAIM_TTL, how long a reviewer keeps claimed locations (a duration, 15m by default).
+func NewReviewHandler(repo *repository.Repository, index *search.LocationIndex) *ReviewHandler {
+	claimTTL := defaultReviewClaimTTL
+	if ttl, err := time.ParseDuration(os.Getenv("REVIEW_CLAIM_TTL")); err == nil && ttl > 0 {
+		claimTTL = ttl
+	}
+
+	return &ReviewHandler{repo: repo, index: index, claimTTL: claimTTL}
+}
+
+// HandleClaim godoc
+//
+//	@Summary	Claim locations whose needs are not verified yet
+//	@Tags		Review
+//	@Produce	json
+//	@Success	200		{object}	feeds.ReviewQueueResponse
+//	@Param		limit	query		integer	false	"Locations to claim, 10 by default, max 50"
+//	@Param		X-Actor	header		string	true	"Reviewer"
+//	@Security	ApiKeyAuth
+//	@Router		/reviews/claim [POST]
+func (h *ReviewHandler) HandleClaim(ctx *fiber.Ctx) error {
+	limit := ctx.QueryInt("limit", feeds.DefaultReviewClaimSize)
+	if limit <= 0 {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+	if limit > feeds.MaxReviewClaimSize {
+		limit = feeds.MaxReviewClaimSize
+	}
+
+	reviewer, err := reviewerOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	data, err := h.repo.ClaimReviews(ctx.UserContext(), reviewer, limit, h.claimTTL)
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.JSON(&feeds.ReviewQueueResponse{
+		Count:   len(data),
+		Results: data,
+	})
+}
+
+// HandleSubmit godoc
+//
+//	@Summary	Verify the intents and needs of a claimed location
+//	@Tags		Review
+//	@Accept		json
+//	@Produce	json
+//	@Success	200					{object}	feeds.ReviewResult
+//	@Param		id					path		integer						true	"Location Id"
+//	@Param		SubmitReviewRequest	body		feeds.SubmitReviewRequest	false	"Corrected intents and needs, empty to confirm them"
+//	@Param		X-Actor				header		string						true	"Reviewer"
+//	@Security	ApiKeyAuth
+//	@Router		/reviews/{id} [POST]
+func (h *ReviewHandler) HandleSubmit(ctx *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	reviewer, err := reviewerOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	var req feeds.SubmitReviewRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return fmt.Errorf("failed to decode request. err: %w", err)
+		}
+	}
+
+	if req.Reason != nil {
+		reason := feeds.NormalizeReasons(*req.Reason)
+		req.Reason = &reason
+	}
+	for i := range req.Needs {
+		req.Needs[i].Label = strings.TrimSpace(req.Needs[i].Label)
+		if req.Needs[i].Label == "" {
+			return fiber.NewError(fiber.StatusBadRequest, "needs must have a label")
+		}
+	}
+
+	result, err := h.repo.SubmitReview(ctx.UserContext(), locationID, reviewer, req)
+	if errors.Is(err, repository.ErrReviewNotClaimed) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	// the claim is released with the submit, a failed elastic update is logged and the location is
+	// searched with its unreviewed needs until it is written again
+	if err := h.index.SetNeedVerification(ctx.UserContext(), result.LocationID, result.Reason, result.Needs); err != nil {
+		log.Logger().Error("could not sync need verification to elastic", zap.Int64("locationID", result.LocationID), zap.Error(err))
+	}
+
+	return ctx.JSON(result)
+}
+
+// HandleRelease godoc
+//
+//	@Summary	Give a claimed location back to the review queue
+//	@Tags		Review
+//	@Success	204
+//	@Param		id		path	integer	true	"Location Id"
+//	@Param		X-Actor	header	string	true	"Reviewer"
+//	@Security	ApiKeyAuth
+//	@Router		/reviews/{id}/claim [DELETE]
+func (h *ReviewHandler) HandleRelease(ctx *fiber.Ctx) error {
+	locationID, err := strconv.ParseInt(ctx.Params("id"), 10, 64)
+	if err != nil {
+		return ctx.SendStatus(fiber.StatusBadRequest)
+	}
+
+	reviewer, err := reviewerOf(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = h.repo.ReleaseReview(ctx.UserContext(), locationID, reviewer)
+	if errors.Is(err, repository.ErrReviewNotClaimed) {
+		return fiber.NewError(fiber.StatusConflict, err.Error())
+	}
+	if err != nil {
+		return ctx.JSON(err)
+	}
+
+	return ctx.SendStatus(fiber.StatusNoContent)
+}
+
+// reviewerOf requires the X-Actor header, claims made under the shared api key could not be told apart.
+func reviewerOf(ctx *fiber.Ctx) (string, error) {
+	if strings.TrimSpace(ctx.Get(auth.ActorHeaderName)) == "" {
+		return "", fiber.NewError(fiber.StatusBadRequest, auth.ActorHeaderName+" header is required for reviews")
+	}
+	return auth.Actor(ctx), nil
+}
@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/gofiber/fiber/v2"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+)
+
+const (
+	// a stream holds a pooled database connection until the last row is written
+	streamTimeout = 2 * time.Minute
+	maxStreams    = 3
+
+	// rows are flushed in batches, a flush blocks while the client is not reading
+	streamFlushEvery = 100
+)
+
+// streamSlots bounds the streams running at once, so slow clients can not take every pooled connection.
+var streamSlots = make(chan struct{}, maxStreams)
+
+// streamTrailer is the last line of a paged stream which has a next page.
+type streamTrailer struct {
+	NextCursor string `json:"next_cursor"`
+}
+
+// streamLocations writes the locations as newline delimited json while iterating the database rows.
+// The stream writer runs after the handler returned, once fasthttp starts sending the body. A failing
+// write means the client went away, it cancels the query context so the rows are released.
+func streamLocations(ctx *fiber.Ctx, repo *repository.Repository, getLocationsQuery *repository.GetLocationsQuery) error {
+	select {
+	case streamSlots <- struct{}{}:
+	default:
+		return fiber.NewError(fiber.StatusServiceUnavailable, "too many location streams, retry later")
+	}
+
+	ctx.Set(fiber.HeaderContentType, MIMEApplicationNDJSON)
+
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer func() { <-streamSlots }()
+
+		queryCtx, cancel := context.WithTimeout(context.Background(), streamTimeout)
+		defer cancel()
+
+		stream := jsoniter.NewStream(jsoniter.ConfigDefault, w, 4096)
+		written := 0
+
+		next, err := repo.StreamLocations(queryCtx, getLocationsQuery, func(location feeds.Location) error {
+			if err := writeLine(stream, location); err != nil {
+				return err
+			}
+
+			written++
+			if written%streamFlushEvery == 0 {
+				return w.Flush()
+			}
+			return nil
+		})
+		if err == nil && next != nil {
+			err = writeLine(stream, streamTrailer{NextCursor: next.Encode()})
+		}
+		if err != nil {
+			cancel()
+			log.Logger().Info("location stream stopped", zap.Int("written", written), zap.Error(err))
+			return
+		}
+
+		if err := w.Flush(); err != nil {
+			log.Logger().Info("location stream flush failed", zap.Int("written", written), zap.Error(err))
+		}
+	})
+
+	return nil
+}
+
+// writeLine writes v as one line of the stream.
+func writeLine(stream *jsoniter.Stream, v interface{}) error {
+	stream.WriteVal(v)
+	if stream.Error != nil {
+		return stream.Error
+	}
+
+	stream.WriteRaw("\n")
+	return stream.Flush()
+}
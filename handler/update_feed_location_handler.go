@@ -5,6 +5,7 @@ import (
 	"net/http"
 
 	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/middleware/auth"
 	"github.com/acikkaynak/backend-api-go/repository"
 	"github.com/gofiber/fiber/v2"
 )
@@ -17,6 +18,7 @@ import (
 //	@Produce	json
 //	@Success	202
 //	@Param		UpdateFeedLocationsRequest	body	feeds.UpdateFeedLocationsRequest	true	"RequestBody"
+//	@Param		X-Actor						header	string								false	"Who corrected the locations"
 //	@Security	ApiKeyAuth
 //	@Router		/feeds/areas [PATCH]
 func UpdateFeedLocationsHandler(repo *repository.Repository) fiber.Handler {
@@ -27,7 +29,7 @@ func UpdateFeedLocationsHandler(repo *repository.Repository) fiber.Handler {
 			return fmt.Errorf("failed to decode request. err: %w", err)
 		}
 
-		err := repo.UpdateFeedLocations(ctx.Context(), req.FeedLocations)
+		err := repo.UpdateFeedLocations(ctx.Context(), req.FeedLocations, auth.Actor(ctx))
 		if err != nil {
 			return ctx.JSON(err)
 		}
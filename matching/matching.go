@@ -0,0 +1,243 @@
+// Package matching proposes where an offer could go and what could cover a need: the nearest open needs
+// and feed locations asking for an offer's categories, or the nearest active offers of a need's categories.
+package matching
+
+import (
+	"context"
+	"errors"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/acikkaynak/backend-api-go/offers"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"go.uber.org/zap"
+)
+
+const (
+	KindNeed         = "need"
+	KindFeedLocation = "feed_location"
+	KindOffer        = "offer"
+
+	defaultRadiusM = 50000.0
+	DefaultLimit   = 20
+	MaxLimit       = 100
+)
+
+// ErrNotLocated is returned for needs whose address is not geocoded yet, they have nothing to be near to.
+var ErrNotLocated = errors.New("need is not geocoded yet")
+
+// Match is a proposal, Categories are the categories asked for on one side and offered on the other.
+type Match struct {
+	Kind        string    `json:"kind"`
+	ID          int64     `json:"id"`
+	EntryID     int64     `json:"entry_id,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Address     string    `json:"address,omitempty"`
+	Loc         []float64 `json:"loc"`
+	DistanceM   float64   `json:"distance_m"`
+	Categories  []string  `json:"categories"`
+}
+
+type Response struct {
+	Count   int     `json:"count"`
+	Results []Match `json:"results"`
+}
+
+// Store reads the candidates of a match, implemented by repository.Repository. The nearby reads return
+// candidates within radiusM meters asking for or offering any of the categories, nearest first.
+type Store interface {
+	GetOffer(ctx context.Context, id int64) (*offers.Offer, error)
+	GetNeed(ctx context.Context, id int64) (*needs.Need, error)
+	GetNearbyNeeds(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]needs.Need, error)
+	GetNearbyFeedLocations(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]feeds.Location, error)
+	GetNearbyOffers(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]offers.Offer, error)
+}
+
+type Service struct {
+	store   Store
+	radiusM float64
+}
+
+// NewService reads MATCH_RADIUS_M, how far in meters candidates are looked for (50km by default, at most 100km).
+func NewService(store Store) *Service {
+	radiusM := defaultRadiusM
+	if env := os.Getenv("MATCH_RADIUS_M"); env != "" {
+		r, err := strconv.ParseFloat(env, 64)
+		if err != nil || r <= 0 || r > feeds.MaxRadiusM {
+			log.Logger().Error("invalid MATCH_RADIUS_M, using the default", zap.String("value", env))
+		} else {
+			radiusM = r
+		}
+	}
+
+	return &Service{store: store, radiusM: radiusM}
+}
+
+// ForOffer proposes the nearest open needs and feed locations asking for the offer's categories.
+// Inactive offers have no matches.
+func (s *Service) ForOffer(ctx context.Context, offerID int64, limit int) ([]Match, error) {
+	offer, err := s.store.GetOffer(ctx, offerID)
+	if err != nil {
+		return nil, err
+	}
+	if !offer.IsActive {
+		return []Match{}, nil
+	}
+
+	lat, lng := offer.Loc[0], offer.Loc[1]
+	categories := offer.CategoryNames()
+
+	nearbyNeeds, err := s.store.GetNearbyNeeds(ctx, lat, lng, s.radiusM, categories, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	locations, err := s.store.GetNearbyFeedLocations(ctx, lat, lng, s.radiusM, categories, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(nearbyNeeds)+len(locations))
+	for _, need := range nearbyNeeds {
+		matched := intersect(categories, needCategoryNames(need.Categories))
+		if len(matched) == 0 {
+			continue
+		}
+		matches = append(matches, Match{
+			Kind:        KindNeed,
+			ID:          need.ID,
+			Description: need.Description,
+			Address:     firstNonEmpty(need.FormattedAddress, need.Address),
+			Loc:         need.Loc,
+			DistanceM:   feeds.Distance(lat, lng, need.Loc[0], need.Loc[1]),
+			Categories:  matched,
+		})
+	}
+
+	for _, location := range locations {
+		matched := LocationCategories(categories, location.Reason, location.Needs)
+		if len(matched) == 0 {
+			continue
+		}
+		matches = append(matches, Match{
+			Kind:       KindFeedLocation,
+			ID:         location.ID,
+			EntryID:    location.EntryID,
+			Loc:        []float64{location.Latitude, location.Longitude},
+			DistanceM:  feeds.Distance(lat, lng, location.Latitude, location.Longitude),
+			Categories: matched,
+		})
+	}
+
+	return rank(matches, limit), nil
+}
+
+// ForNeed proposes the nearest active offers of the need's categories. Resolved needs and needs without
+// categories have no matches, needs which are not geocoded yet return ErrNotLocated.
+func (s *Service) ForNeed(ctx context.Context, needID int64, limit int) ([]Match, error) {
+	need, err := s.store.GetNeed(ctx, needID)
+	if err != nil {
+		return nil, err
+	}
+	if need.IsResolved || len(need.Categories) == 0 {
+		return []Match{}, nil
+	}
+	if need.GeocodeStatus != needs.GeocodeStatusResolved {
+		return nil, ErrNotLocated
+	}
+
+	lat, lng := need.Loc[0], need.Loc[1]
+	categories := needCategoryNames(need.Categories)
+
+	nearbyOffers, err := s.store.GetNearbyOffers(ctx, lat, lng, s.radiusM, categories, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]Match, 0, len(nearbyOffers))
+	for _, offer := range nearbyOffers {
+		matched := intersect(categories, offer.CategoryNames())
+		if len(matched) == 0 {
+			continue
+		}
+		matches = append(matches, Match{
+			Kind:        KindOffer,
+			ID:          offer.ID,
+			Description: offer.Description,
+			Address:     offer.Address,
+			Loc:         offer.Loc,
+			DistanceM:   feeds.Distance(lat, lng, offer.Loc[0], offer.Loc[1]),
+			Categories:  matched,
+		})
+	}
+
+	return rank(matches, limit), nil
+}
+
+// LocationCategories returns the categories a feed location asks for, out of its reasons and the labels
+// of its open needs. Reasons are matched by substring like the reason filter of the location endpoints.
+func LocationCategories(categories []string, reason *string, items []feeds.NeedItem) []string {
+	var matched []string
+	for _, category := range categories {
+		asked := reason != nil && strings.Contains(strings.ToLower(*reason), category)
+		for _, item := range items {
+			if item.Status && strings.EqualFold(strings.TrimSpace(item.Label), category) {
+				asked = true
+			}
+		}
+		if asked {
+			matched = append(matched, category)
+		}
+	}
+	return matched
+}
+
+// rank orders matches nearest first, matches covering more categories first at the same distance,
+// and keeps the first limit of them.
+func rank(matches []Match, limit int) []Match {
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].DistanceM != matches[j].DistanceM {
+			return matches[i].DistanceM < matches[j].DistanceM
+		}
+		return len(matches[i].Categories) > len(matches[j].Categories)
+	})
+
+	if limit > 0 && len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+func needCategoryNames(categories []needs.Category) []string {
+	names := make([]string, 0, len(categories))
+	for _, c := range categories {
+		names = append(names, c.Category)
+	}
+	return names
+}
+
+func intersect(a, b []string) []string {
+	var both []string
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				both = append(both, x)
+				break
+			}
+		}
+	}
+	return both
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
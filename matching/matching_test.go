@@ -0,0 +1,102 @@
+package matching
+
+import (
+	"context"
+	"testing"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/acikkaynak/backend-api-go/offers"
+	"github.com/stretchr/testify/assert"
+)
+
+// adana is where the offers of the tests are, the candidates are returned as if the store filtered them.
+var adana = []float64{37.0, 35.32}
+
+type fakeStore struct {
+	offer     *offers.Offer
+	need      *needs.Need
+	needs     []needs.Need
+	locations []feeds.Location
+	offers    []offers.Offer
+}
+
+func (s *fakeStore) GetOffer(context.Context, int64) (*offers.Offer, error) {
+	return s.offer, nil
+}
+
+func (s *fakeStore) GetNeed(context.Context, int64) (*needs.Need, error) {
+	return s.need, nil
+}
+
+func (s *fakeStore) GetNearbyNeeds(context.Context, float64, float64, float64, []string, int) ([]needs.Need, error) {
+	return s.needs, nil
+}
+
+func (s *fakeStore) GetNearbyFeedLocations(context.Context, float64, float64, float64, []string, int) ([]feeds.Location, error) {
+	return s.locations, nil
+}
+
+func (s *fakeStore) GetNearbyOffers(context.Context, float64, float64, float64, []string, int) ([]offers.Offer, error) {
+	return s.offers, nil
+}
+
+func TestForOffer(t *testing.T) {
+	reason := "barınma,battaniye"
+	store := &fakeStore{
+		offer: &offers.Offer{ID: 1, IsActive: true, Loc: adana, Categories: []needs.Category{{Category: "battaniye"}, {Category: "çadır"}}},
+		needs: []needs.Need{
+			{ID: 10, Description: "far", Loc: []float64{37.2, 35.32}, Categories: []needs.Category{{Category: "battaniye", Quantity: 50}}},
+			{ID: 11, Description: "other category", Loc: adana, Categories: []needs.Category{{Category: "su"}}},
+		},
+		locations: []feeds.Location{
+			{ID: 20, EntryID: 20, Latitude: 37.01, Longitude: 35.32, Reason: &reason},
+			{ID: 21, EntryID: 21, Latitude: 37.0, Longitude: 35.32, Needs: []feeds.NeedItem{{Label: "Çadır", Status: false}}},
+			{ID: 22, EntryID: 22, Latitude: 37.05, Longitude: 35.32, Needs: []feeds.NeedItem{{Label: "çadır", Status: true}}},
+		},
+	}
+
+	matches, err := NewService(store).ForOffer(context.Background(), 1, 10)
+	assert.NoError(t, err)
+
+	var got []int64
+	for _, m := range matches {
+		got = append(got, m.ID)
+	}
+	assert.Equal(t, []int64{20, 22, 10}, got, "nearest first, without candidates of other or satisfied categories")
+	assert.Equal(t, KindFeedLocation, matches[0].Kind)
+	assert.Equal(t, []string{"battaniye"}, matches[0].Categories)
+	assert.Equal(t, []string{"çadır"}, matches[1].Categories)
+	assert.Equal(t, KindNeed, matches[2].Kind)
+
+	matches, err = NewService(store).ForOffer(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	store.offer.IsActive = false
+	matches, err = NewService(store).ForOffer(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, matches)
+}
+
+func TestForNeed(t *testing.T) {
+	store := &fakeStore{
+		need: &needs.Need{ID: 1, Loc: adana, GeocodeStatus: needs.GeocodeStatusPending, Categories: []needs.Category{{Category: "su"}}},
+		offers: []offers.Offer{
+			{ID: 30, Loc: []float64{37.1, 35.32}, Categories: []needs.Category{{Category: "su"}, {Category: "gıda"}}},
+			{ID: 31, Loc: adana, Categories: []needs.Category{{Category: "battaniye"}}},
+		},
+	}
+
+	_, err := NewService(store).ForNeed(context.Background(), 1, 10)
+	assert.ErrorIs(t, err, ErrNotLocated)
+
+	store.need.GeocodeStatus = needs.GeocodeStatusResolved
+	matches, err := NewService(store).ForNeed(context.Background(), 1, 10)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+	assert.Equal(t, int64(30), matches[0].ID)
+	assert.Equal(t, KindOffer, matches[0].Kind)
+	assert.Equal(t, []string{"su"}, matches[0].Categories)
+	assert.InDelta(t, 11132, matches[0].DistanceM, 50)
+}
@@ -7,7 +7,14 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
-const ApiKeyHeaderName = "X-Api-Key"
+const (
+	ApiKeyHeaderName = "X-Api-Key"
+	// ActorHeaderName names the person behind a request made with the shared api key, tools calling
+	// write endpoints on behalf of an operator set it so changes can be attributed.
+	ActorHeaderName = "X-Actor"
+
+	defaultActor = "api_key"
+)
 
 var restrictedHttpMethods = map[string]struct{}{
 	"POST":   {},
@@ -22,7 +29,8 @@ func New() fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		apiKeyNeeded := false
 		_, restrictedMethod := restrictedHttpMethods[ctx.Method()]
-		if strings.Contains(ctx.Path(), "pprof") || strings.Contains(ctx.Path(), "swagger") || restrictedMethod {
+		if strings.Contains(ctx.Path(), "pprof") || strings.Contains(ctx.Path(), "swagger") ||
+			strings.HasPrefix(ctx.Path(), "/admin") || strings.HasPrefix(ctx.Path(), "/feeds/export") || restrictedMethod {
 			apiKeyNeeded = true
 		}
 
@@ -33,3 +41,11 @@ func New() fiber.Handler {
 		return ctx.Next()
 	}
 }
+
+// Actor returns who made the request for audit fields, the X-Actor header or the api key itself.
+func Actor(ctx *fiber.Ctx) string {
+	if actor := strings.TrimSpace(ctx.Get(ActorHeaderName)); actor != "" {
+		return actor
+	}
+	return defaultActor
+}
@@ -1,8 +1,10 @@
 package cache
 
 import (
+	"bytes"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/acikkaynak/backend-api-go/cache"
@@ -10,38 +12,120 @@ import (
 	"github.com/google/uuid"
 )
 
+// cached entries are stored as "<content type>\n<body>" so negotiated formats are replayed as they were sent
+const entrySeparator = '\n'
+
+// negotiatedTypes are the Accept header media types that change a response body for the same url.
+var negotiatedTypes = []string{
+	"application/geo+json",
+	"application/x-ndjson",
+}
+
 func New() fiber.Handler {
 	cacheRepo := cache.NewRedisRepository()
 	return func(c *fiber.Ctx) error {
-		if c.Path() == "/healthcheck" ||
-			c.Path() == "/metrics" ||
-			c.Path() == "/monitor" {
-			return c.Next()
-		}
-
 		reqURI := c.OriginalURL()
-		hashURL := uuid.NewSHA1(uuid.NameSpaceOID, []byte(reqURI)).String()
 		if c.Method() != http.MethodGet {
 			// Don't cache write endpoints. We can maintain of list to exclude certain http methods later.
 			// Since there will be an update in db, better to remove cache entries for this url
-			err := cacheRepo.Delete(hashURL)
-			if err != nil {
-				fmt.Println(err)
+			// and for the resource it acts on
+			for _, key := range invalidatedKeys(reqURI, c.Path()) {
+				if err := cacheRepo.Delete(key); err != nil {
+					fmt.Println(err)
+				}
 			}
 			return c.Next()
 		}
-		cacheData := cacheRepo.Get(hashURL)
-		if cacheData == nil || len(cacheData) == 0 {
+
+		// sync clients poll /feeds/changes with the same token until something changes,
+		// location history and admin lists are read right after the changes they show,
+		// exports are streamed and every one of them has to reach the export log
+		if c.Path() == "/healthcheck" ||
+			c.Path() == "/metrics" ||
+			c.Path() == "/monitor" ||
+			c.Path() == "/feeds/changes" ||
+			c.Path() == "/feeds/export" ||
+			strings.HasSuffix(c.Path(), "/history") ||
+			strings.HasPrefix(c.Path(), "/admin") {
+			return c.Next()
+		}
+
+		cacheKey := keyOf("", reqURI)
+		if mediaType := negotiatedType(c); mediaType != "" {
+			cacheKey = keyOf(mediaType, reqURI)
+		}
+
+		cacheData := cacheRepo.Get(cacheKey)
+		contentType, body, ok := decodeEntry(cacheData)
+		if !ok {
 			c.Next()
+			// streamed bodies are not cached, reading them here would buffer the whole stream
+			if c.Response().IsBodyStream() {
+				return nil
+			}
 			if c.Response().StatusCode() == fiber.StatusOK && len(c.Response().Body()) > 0 {
-				cacheRepo.SetKey(hashURL, c.Response().Body(), 5*time.Minute)
+				cacheRepo.SetKey(cacheKey, encodeEntry(c.Response().Header.ContentType(), c.Response().Body()), 5*time.Minute)
 			}
 			return nil
 		}
 
 		c.Set("x-cached-response", "true")
-		c.Response().SetBodyRaw(cacheData)
-		c.Response().Header.SetContentType(fiber.MIMEApplicationJSON)
+		c.Response().SetBodyRaw(body)
+		c.Response().Header.SetContentTypeBytes(contentType)
 		return nil
 	}
 }
+
+// keyOf returns the cache key of a url read as mediaType, an empty mediaType is the default format.
+func keyOf(mediaType, reqURI string) string {
+	if mediaType != "" {
+		reqURI = mediaType + " " + reqURI
+	}
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(reqURI)).String()
+}
+
+// invalidatedKeys returns the cache keys a write to reqURI makes outdated: its own and the ones of the
+// resources it acts on, so POST /feeds/1/resolve drops the cached GET /feeds/1/ in every format.
+// Admin writes act on the public resource of the same path.
+func invalidatedKeys(reqURI, path string) []string {
+	keys := []string{keyOf("", reqURI)}
+
+	segments := strings.Split(strings.Trim(strings.TrimPrefix(path, "/admin"), "/"), "/")
+	// collections like /feeds are cached per query string and expire on their own
+	for i := len(segments); i >= 2; i-- {
+		resource := "/" + strings.Join(segments[:i], "/")
+		for _, url := range []string{resource, resource + "/"} {
+			keys = append(keys, keyOf("", url))
+			for _, mediaType := range negotiatedTypes {
+				keys = append(keys, keyOf(mediaType, url))
+			}
+		}
+	}
+
+	return keys
+}
+
+func negotiatedType(c *fiber.Ctx) string {
+	accept := c.Get(fiber.HeaderAccept)
+	for _, mediaType := range negotiatedTypes {
+		if strings.Contains(accept, mediaType) {
+			return mediaType
+		}
+	}
+	return ""
+}
+
+func encodeEntry(contentType, body []byte) []byte {
+	entry := make([]byte, 0, len(contentType)+1+len(body))
+	entry = append(entry, contentType...)
+	entry = append(entry, entrySeparator)
+	return append(entry, body...)
+}
+
+func decodeEntry(entry []byte) (contentType, body []byte, ok bool) {
+	i := bytes.IndexByte(entry, entrySeparator)
+	if i <= 0 || i == len(entry)-1 {
+		return nil, nil, false
+	}
+	return entry[:i], entry[i+1:], true
+}
@@ -0,0 +1,21 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInvalidatedKeys(t *testing.T) {
+	keys := invalidatedKeys("/feeds/12/resolve", "/feeds/12/resolve")
+	assert.Contains(t, keys, keyOf("", "/feeds/12/resolve"))
+	assert.Contains(t, keys, keyOf("", "/feeds/12/"))
+	assert.Contains(t, keys, keyOf("application/geo+json", "/feeds/12/"))
+	assert.NotContains(t, keys, keyOf("", "/feeds"))
+
+	keys = invalidatedKeys("/admin/feeds/12/restore", "/admin/feeds/12/restore")
+	assert.Contains(t, keys, keyOf("", "/feeds/12/"))
+
+	keys = invalidatedKeys("/needs/7/merge", "/needs/7/merge")
+	assert.Contains(t, keys, keyOf("", "/needs/7"))
+}
@@ -0,0 +1,50 @@
+package needs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+)
+
+const maxUnitLength = 32
+
+var ErrInvalidCategory = errors.New("invalid need category")
+
+// Category is what a need asks for, a reason out of the /reasons taxonomy with an optional
+// quantity and unit like 50 battaniye or 200 litre su.
+type Category struct {
+	Category string `json:"category"`
+	Quantity int    `json:"quantity,omitempty"`
+	Unit     string `json:"unit,omitempty"`
+}
+
+// NormalizeCategories trims and lower cases categories and rejects categories out of the taxonomy,
+// negative quantities, units without a quantity and categories given twice.
+func NormalizeCategories(categories []Category) error {
+	seen := make(map[string]struct{}, len(categories))
+	for i := range categories {
+		c := &categories[i]
+		c.Category = feeds.NormalizeReason(c.Category)
+		c.Unit = strings.TrimSpace(c.Unit)
+
+		if !feeds.ValidReason(c.Category) {
+			return fmt.Errorf("%w: %q is not one of /reasons", ErrInvalidCategory, c.Category)
+		}
+		if c.Quantity < 0 {
+			return fmt.Errorf("%w: quantity of %s can not be negative", ErrInvalidCategory, c.Category)
+		}
+		if c.Unit != "" && c.Quantity == 0 {
+			return fmt.Errorf("%w: unit of %s needs a quantity", ErrInvalidCategory, c.Category)
+		}
+		if len(c.Unit) > maxUnitLength {
+			return fmt.Errorf("%w: unit of %s is longer than %d characters", ErrInvalidCategory, c.Category, maxUnitLength)
+		}
+		if _, ok := seen[c.Category]; ok {
+			return fmt.Errorf("%w: %s is given twice", ErrInvalidCategory, c.Category)
+		}
+		seen[c.Category] = struct{}{}
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package needs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeCategories(t *testing.T) {
+	categories := []Category{{Category: " Battaniye ", Quantity: 50, Unit: " adet "}, {Category: "su"}}
+	assert.NoError(t, NormalizeCategories(categories))
+	assert.Equal(t, []Category{{Category: "battaniye", Quantity: 50, Unit: "adet"}, {Category: "su"}}, categories)
+
+	invalid := [][]Category{
+		{{Category: "uçak"}},
+		{{Category: "su", Quantity: -1}},
+		{{Category: "su", Unit: "litre"}},
+		{{Category: "su"}, {Category: "SU"}},
+	}
+	for _, categories := range invalid {
+		assert.ErrorIs(t, NormalizeCategories(categories), ErrInvalidCategory, "%v", categories)
+	}
+}
+
+func TestCreateNeedRequestValidate(t *testing.T) {
+	req := CreateNeedRequest{Address: " Antakya ", Description: "battaniye lazım"}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "Antakya", req.Address)
+
+	assert.ErrorIs(t, (&CreateNeedRequest{Address: "Antakya"}).Validate(), ErrEmptyField)
+	assert.ErrorIs(t, (&CreateNeedRequest{Address: "Antakya", Description: "x", Categories: []Category{{Category: "x"}}}).Validate(), ErrInvalidCategory)
+}
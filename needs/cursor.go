@@ -0,0 +1,79 @@
+package needs
+
+import (
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Sort orders of the needs list, SortDistance needs a radius filter and is not cursor paginated.
+const (
+	SortNewest   = "newest"
+	SortOldest   = "oldest"
+	SortDistance = "distance"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last need of a page. Pages are ordered by timestamp and id, the timestamp
+// is kept in microseconds like postgres stores it so no need is skipped or repeated.
+type Cursor struct {
+	Timestamp time.Time
+	ID        int64
+}
+
+func (c Cursor) Encode() string {
+	raw := strconv.FormatInt(c.Timestamp.UnixMicro(), 10) + ":" + strconv.FormatInt(c.ID, 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (*Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.SplitN(string(raw), ":", 2)
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+
+	micros, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &Cursor{Timestamp: time.UnixMicro(micros), ID: id}, nil
+}
+
+func ValidSort(sort string) bool {
+	switch sort {
+	case "", SortNewest, SortOldest, SortDistance:
+		return true
+	}
+	return false
+}
+
+// NewResponse builds a response page out of total matching needs. Readers fetch one row more than
+// limit, so a surplus row means there is a next page.
+func NewResponse(results []Need, limit, total int) *Response {
+	resp := &Response{Total: total}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+		last := results[len(results)-1]
+		resp.NextCursor = Cursor{Timestamp: last.Timestamp, ID: last.ID}.Encode()
+	}
+
+	resp.Count = len(results)
+	resp.Results = results
+
+	return resp
+}
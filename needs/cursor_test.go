@@ -0,0 +1,34 @@
+package needs
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	cursor := Cursor{Timestamp: time.UnixMicro(1675945487123456), ID: 42}
+
+	decoded, err := DecodeCursor(cursor.Encode())
+	assert.NoError(t, err)
+	assert.True(t, cursor.Timestamp.Equal(decoded.Timestamp))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	_, err = DecodeCursor("not-a-cursor")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewResponse(t *testing.T) {
+	now := time.Now()
+	results := []Need{{ID: 3, Timestamp: now}, {ID: 2, Timestamp: now.Add(-time.Minute)}, {ID: 1, Timestamp: now.Add(-time.Hour)}}
+
+	resp := NewResponse(results, 2, 7)
+	assert.Equal(t, 2, resp.Count)
+	assert.Equal(t, 7, resp.Total)
+	assert.Equal(t, Cursor{Timestamp: now.Add(-time.Minute), ID: 2}.Encode(), resp.NextCursor)
+
+	resp = NewResponse(results, 3, 3)
+	assert.Equal(t, 3, resp.Count)
+	assert.Empty(t, resp.NextCursor)
+}
@@ -0,0 +1,158 @@
+package needs
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DuplicateThreshold is the description similarity from which a need at the same address is a likely duplicate.
+const DuplicateThreshold = 0.4
+
+// Duplicate is an existing need a new one likely repeats.
+type Duplicate struct {
+	Need
+	Similarity float64 `json:"similarity"`
+}
+
+// DuplicatesResponse is returned instead of creating a need which likely repeats existing ones,
+// sending the request again with confirm set creates it anyway.
+type DuplicatesResponse struct {
+	ConfirmRequired bool        `json:"confirm_required"`
+	Duplicates      []Duplicate `json:"duplicates"`
+}
+
+var ErrInvalidMerge = errors.New("duplicate_ids must list other needs than the one they are merged into")
+
+// MergeNeedsRequest lists the needs merged into the need of the path.
+type MergeNeedsRequest struct {
+	DuplicateIDs []int64 `json:"duplicate_ids"`
+}
+
+// Validate removes repeated ids and rejects an empty list or one containing the target need.
+func (r *MergeNeedsRequest) Validate(targetID int64) error {
+	seen := make(map[int64]struct{}, len(r.DuplicateIDs))
+	ids := r.DuplicateIDs[:0]
+	for _, id := range r.DuplicateIDs {
+		if id == targetID {
+			return ErrInvalidMerge
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return ErrInvalidMerge
+	}
+	r.DuplicateIDs = ids
+	return nil
+}
+
+// foldTurkish maps Turkish letters to their ASCII look-alikes, operators type addresses both ways.
+var foldTurkish = strings.NewReplacer("ç", "c", "ğ", "g", "ı", "i", "ö", "o", "ş", "s", "ü", "u", "â", "a", "î", "i", "û", "u")
+
+// addressAbbreviations are expanded so "Cumhuriyet Mah. 5. Sk." and "cumhuriyet mahallesi 5 sokak" normalize alike.
+var addressAbbreviations = map[string]string{
+	"mah":     "mahallesi",
+	"mh":      "mahallesi",
+	"mahalle": "mahallesi",
+	"sok":     "sokak",
+	"sk":      "sokak",
+	"sokagi":  "sokak",
+	"cad":     "caddesi",
+	"cd":      "caddesi",
+	"cadde":   "caddesi",
+	"apt":     "apartmani",
+	"blv":     "bulvari",
+	"bulv":    "bulvari",
+}
+
+// tokens lower cases s the Turkish way, folds Turkish letters and splits it into words of letters and digits.
+func tokens(s string) []string {
+	s = foldTurkish.Replace(strings.ToLowerSpecial(unicode.TurkishCase, s))
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NormalizeAddress returns the form addresses are compared in, case, punctuation, Turkish letters and
+// common abbreviations do not make two addresses differ.
+func NormalizeAddress(address string) string {
+	words := tokens(address)
+	for i, w := range words {
+		if expanded, ok := addressAbbreviations[w]; ok {
+			words[i] = expanded
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// DescriptionSimilarity returns the trigram similarity of two descriptions between 0 and 1, the share of
+// word trigrams they have in common like pg_trgm computes it. Word order and small typos barely lower it.
+func DescriptionSimilarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	common := 0
+	for t := range ta {
+		if _, ok := tb[t]; ok {
+			common++
+		}
+	}
+
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+func trigrams(s string) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, w := range tokens(s) {
+		padded := []rune("  " + w + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = struct{}{}
+		}
+	}
+	return set
+}
+
+// FindDuplicates returns the candidates whose description is at least DuplicateThreshold similar to
+// description, most similar first. Candidates are expected to share the new need's address.
+func FindDuplicates(description string, candidates []Need) []Duplicate {
+	var duplicates []Duplicate
+	for _, candidate := range candidates {
+		if similarity := DescriptionSimilarity(description, candidate.Description); similarity >= DuplicateThreshold {
+			duplicates = append(duplicates, Duplicate{Need: candidate, Similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+
+	return duplicates
+}
+
+// MergeCategories combines the categories of merged needs. Duplicates ask for the same supplies, so a
+// category keeps its largest quantity instead of adding them up.
+func MergeCategories(lists ...[]Category) []Category {
+	merged := []Category{}
+	index := make(map[string]int)
+	for _, list := range lists {
+		for _, c := range list {
+			i, ok := index[c.Category]
+			if !ok {
+				index[c.Category] = len(merged)
+				merged = append(merged, c)
+				continue
+			}
+			if c.Quantity > merged[i].Quantity {
+				merged[i] = c
+			}
+		}
+	}
+	return merged
+}
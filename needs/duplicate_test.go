@@ -0,0 +1,51 @@
+package needs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAddress(t *testing.T) {
+	assert.Equal(t, "cumhuriyet mahallesi 5 sokak no 3 iskenderun",
+		NormalizeAddress("Cumhuriyet Mah. 5. Sk. No:3, İSKENDERUN"))
+	assert.Equal(t, NormalizeAddress("Şükrükanatlı Mahallesi, Antakya"), NormalizeAddress("sukrukanatli mh antakya"))
+}
+
+func TestDescriptionSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, DescriptionSimilarity("3 battaniye lazım", "Lazım: 3 BATTANİYE"))
+	assert.GreaterOrEqual(t, DescriptionSimilarity("50 battaniye ve çadır lazım", "çadir ve battanye lazım 50 adet"), DuplicateThreshold)
+	assert.Less(t, DescriptionSimilarity("50 battaniye lazım", "bebek maması ve bez"), DuplicateThreshold)
+	assert.Equal(t, 0.0, DescriptionSimilarity("", "bebek maması"))
+}
+
+func TestFindDuplicates(t *testing.T) {
+	candidates := []Need{
+		{ID: 1, Description: "bebek maması ve bez"},
+		{ID: 2, Description: "battaniye lazım 30 adet"},
+		{ID: 3, Description: "30 battaniye lazım"},
+	}
+
+	duplicates := FindDuplicates("30 battaniye lazım", candidates)
+	assert.Len(t, duplicates, 2)
+	assert.Equal(t, int64(3), duplicates[0].ID)
+	assert.Equal(t, int64(2), duplicates[1].ID)
+}
+
+func TestMergeCategories(t *testing.T) {
+	merged := MergeCategories(
+		[]Category{{Category: "battaniye", Quantity: 30, Unit: "adet"}},
+		[]Category{{Category: "battaniye", Quantity: 50, Unit: "adet"}, {Category: "su"}},
+		nil,
+	)
+	assert.Equal(t, []Category{{Category: "battaniye", Quantity: 50, Unit: "adet"}, {Category: "su"}}, merged)
+}
+
+func TestMergeNeedsRequestValidate(t *testing.T) {
+	req := MergeNeedsRequest{DuplicateIDs: []int64{4, 5, 4}}
+	assert.NoError(t, req.Validate(1))
+	assert.Equal(t, []int64{4, 5}, req.DuplicateIDs)
+
+	assert.ErrorIs(t, (&MergeNeedsRequest{DuplicateIDs: []int64{1, 4}}).Validate(1), ErrInvalidMerge)
+	assert.ErrorIs(t, (&MergeNeedsRequest{}).Validate(1), ErrInvalidMerge)
+}
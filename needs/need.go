@@ -1,22 +1,117 @@
 package needs
 
 import (
+	"errors"
+	"strings"
 	"time"
 )
 
+// Geocoding states of a need, new needs are pending until the geocoding worker resolved their address.
+const (
+	GeocodeStatusPending  = "pending"
+	GeocodeStatusResolved = "resolved"
+	GeocodeStatusNotFound = "not_found"
+	GeocodeStatusFailed   = "failed"
+)
+
+// Actions recorded in a need's history.
+const (
+	ActionCreated  = "created"
+	ActionUpdated  = "updated"
+	ActionResolved = "resolved"
+	ActionDeleted  = "deleted"
+	ActionMerged   = "merged"
+)
+
+var (
+	ErrEmptyUpdate = errors.New("need update has no fields")
+	ErrEmptyField  = errors.New("need description and address can not be empty")
+)
+
 type CreateNeedRequest struct {
-	Address     string `validate:"required"`
-	Description string `validate:"required"`
+	Address     string     `validate:"required"`
+	Description string     `validate:"required"`
+	Categories  []Category `json:"categories"`
+	// Confirm creates the need even if it likely repeats an existing one
+	Confirm bool `json:"confirm"`
+}
+
+// Validate trims the request and rejects a missing address or description and invalid categories.
+func (r *CreateNeedRequest) Validate() error {
+	r.Address = strings.TrimSpace(r.Address)
+	r.Description = strings.TrimSpace(r.Description)
+	if r.Address == "" || r.Description == "" {
+		return ErrEmptyField
+	}
+	return NormalizeCategories(r.Categories)
 }
 
 type Need struct {
-	ID               int64     `json:"id,omitempty"`
-	Description      string    `json:"description"`
-	IsResolved       bool      `json:"is_resolved"`
-	Timestamp        time.Time `json:"timestamp"`
-	ExtraParameters  *string   `json:"extra_parameters,omitempty"`
-	FormattedAddress string    `json:"formatted_address,omitempty"`
-	Loc              []float64 `json:"loc"`
+	ID               int64      `json:"id,omitempty"`
+	Description      string     `json:"description"`
+	IsResolved       bool       `json:"is_resolved"`
+	Timestamp        time.Time  `json:"timestamp"`
+	ExtraParameters  *string    `json:"extra_parameters,omitempty"`
+	FormattedAddress string     `json:"formatted_address,omitempty"`
+	Loc              []float64  `json:"loc"`
+	GeocodeStatus    string     `json:"geocode_status,omitempty"`
+	Address          string     `json:"address,omitempty"`
+	CreatedBy        *string    `json:"created_by,omitempty"`
+	UpdatedBy        *string    `json:"updated_by,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+	ResolvedBy       *string    `json:"resolved_by,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	Categories       []Category `json:"categories,omitempty"`
+}
+
+// UpdateNeedRequest edits a need, fields left out are kept. A new address is geocoded again,
+// categories replace the need's categories and an empty list clears them.
+type UpdateNeedRequest struct {
+	Description     *string                `json:"description"`
+	Address         *string                `json:"address"`
+	ExtraParameters map[string]interface{} `json:"extra_parameters"`
+	Categories      []Category             `json:"categories"`
+}
+
+// Validate trims the request and rejects updates without fields, with an empty description or address
+// or with invalid categories.
+func (r *UpdateNeedRequest) Validate() error {
+	if r.Description == nil && r.Address == nil && r.ExtraParameters == nil && r.Categories == nil {
+		return ErrEmptyUpdate
+	}
+	for _, field := range []*string{r.Description, r.Address} {
+		if field == nil {
+			continue
+		}
+		*field = strings.TrimSpace(*field)
+		if *field == "" {
+			return ErrEmptyField
+		}
+	}
+	return NormalizeCategories(r.Categories)
+}
+
+// Change is a recorded change of a need, Previous and Changes hold the changed fields before and after.
+type Change struct {
+	ID        int64                  `json:"id"`
+	NeedID    int64                  `json:"need_id"`
+	Action    string                 `json:"action"`
+	Previous  map[string]interface{} `json:"previous,omitempty"`
+	Changes   map[string]interface{} `json:"changes,omitempty"`
+	ChangedBy string                 `json:"changed_by"`
+	ChangedAt time.Time              `json:"changed_at"`
+}
+
+type HistoryResponse struct {
+	Count   int      `json:"count"`
+	Results []Change `json:"results"`
+}
+
+// GeocodeTask is a need claimed by the geocoding worker, Attempts counts this attempt.
+type GeocodeTask struct {
+	ID       int64
+	Address  string
+	Attempts int
 }
 
 type LiteNeed struct {
@@ -24,6 +119,8 @@ type LiteNeed struct {
 }
 
 type Response struct {
-	Count   int    `json:"count"`
-	Results []Need `json:"results"`
+	Count      int    `json:"count"`
+	Total      int    `json:"total"`
+	Results    []Need `json:"results"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
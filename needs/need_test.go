@@ -0,0 +1,22 @@
+package needs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateNeedRequestValidate(t *testing.T) {
+	assert.ErrorIs(t, (&UpdateNeedRequest{}).Validate(), ErrEmptyUpdate)
+
+	blank := "  "
+	assert.ErrorIs(t, (&UpdateNeedRequest{Address: &blank}).Validate(), ErrEmptyField)
+
+	description := " 3 battaniye "
+	req := UpdateNeedRequest{Description: &description}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "3 battaniye", *req.Description)
+
+	req = UpdateNeedRequest{ExtraParameters: map[string]interface{}{"tel": "05355555555"}}
+	assert.NoError(t, req.Validate())
+}
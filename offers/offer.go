@@ -0,0 +1,127 @@
+// Package offers holds the supplies people offer, like a truck of blankets in Adana, so they can be
+// matched against open needs and feed locations asking for the same categories.
+package offers
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/needs"
+)
+
+var (
+	ErrEmptyUpdate     = errors.New("offer update has no fields")
+	ErrEmptyField      = errors.New("offer description and address can not be empty")
+	ErrInvalidLocation = errors.New("offer latitude and longitude must be set together and be valid coordinates")
+	ErrNoCategory      = errors.New("offer needs at least one category")
+)
+
+type Offer struct {
+	ID          int64            `json:"id"`
+	Description string           `json:"description"`
+	Address     string           `json:"address"`
+	Loc         []float64        `json:"loc"`
+	Categories  []needs.Category `json:"categories"`
+	IsActive    bool             `json:"is_active"`
+	CreatedBy   *string          `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	UpdatedBy   *string          `json:"updated_by,omitempty"`
+	UpdatedAt   *time.Time       `json:"updated_at,omitempty"`
+}
+
+// CategoryNames returns the categories of the offer without their quantities.
+func (o *Offer) CategoryNames() []string {
+	names := make([]string, 0, len(o.Categories))
+	for _, c := range o.Categories {
+		names = append(names, c.Category)
+	}
+	return names
+}
+
+// CreateOfferRequest registers an offer, its location is given as coordinates next to the address text.
+type CreateOfferRequest struct {
+	Description string           `json:"description"`
+	Address     string           `json:"address"`
+	Latitude    float64          `json:"latitude"`
+	Longitude   float64          `json:"longitude"`
+	Categories  []needs.Category `json:"categories"`
+}
+
+// Validate trims the request and rejects missing fields, invalid coordinates and invalid categories.
+func (r *CreateOfferRequest) Validate() error {
+	r.Description = strings.TrimSpace(r.Description)
+	r.Address = strings.TrimSpace(r.Address)
+	if r.Description == "" || r.Address == "" {
+		return ErrEmptyField
+	}
+	if !validLocation(r.Latitude, r.Longitude) {
+		return ErrInvalidLocation
+	}
+	if len(r.Categories) == 0 {
+		return ErrNoCategory
+	}
+	return needs.NormalizeCategories(r.Categories)
+}
+
+// UpdateOfferRequest edits an offer, fields left out are kept and categories replace the offer's categories.
+type UpdateOfferRequest struct {
+	Description *string          `json:"description"`
+	Address     *string          `json:"address"`
+	Latitude    *float64         `json:"latitude"`
+	Longitude   *float64         `json:"longitude"`
+	Categories  []needs.Category `json:"categories"`
+	IsActive    *bool            `json:"is_active"`
+}
+
+// Validate trims the request and rejects updates without fields or with invalid values.
+func (r *UpdateOfferRequest) Validate() error {
+	if r.Description == nil && r.Address == nil && r.Latitude == nil && r.Longitude == nil &&
+		r.Categories == nil && r.IsActive == nil {
+		return ErrEmptyUpdate
+	}
+	for _, field := range []*string{r.Description, r.Address} {
+		if field == nil {
+			continue
+		}
+		*field = strings.TrimSpace(*field)
+		if *field == "" {
+			return ErrEmptyField
+		}
+	}
+	if (r.Latitude == nil) != (r.Longitude == nil) || (r.Latitude != nil && !validLocation(*r.Latitude, *r.Longitude)) {
+		return ErrInvalidLocation
+	}
+	if r.Categories != nil && len(r.Categories) == 0 {
+		return ErrNoCategory
+	}
+	return needs.NormalizeCategories(r.Categories)
+}
+
+// validLocation rejects coordinates out of range and 0,0 which clients send for a missing location.
+func validLocation(lat, lng float64) bool {
+	return lat >= -90 && lat <= 90 && lng >= -180 && lng <= 180 && (lat != 0 || lng != 0)
+}
+
+type Response struct {
+	Count      int     `json:"count"`
+	Results    []Offer `json:"results"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+}
+
+// NewResponse builds a response page. Readers fetch one row more than limit, so a surplus row means
+// there is a next page.
+func NewResponse(results []Offer, limit int) *Response {
+	resp := &Response{}
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+		last := results[len(results)-1]
+		resp.NextCursor = needs.Cursor{Timestamp: last.CreatedAt, ID: last.ID}.Encode()
+	}
+
+	resp.Count = len(results)
+	resp.Results = results
+
+	return resp
+}
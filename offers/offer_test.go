@@ -0,0 +1,53 @@
+package offers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateOfferRequestValidate(t *testing.T) {
+	req := CreateOfferRequest{
+		Description: " Bir tır battaniye ",
+		Address:     "Seyhan, Adana",
+		Latitude:    37.0,
+		Longitude:   35.3,
+		Categories:  []needs.Category{{Category: "Battaniye", Quantity: 2000, Unit: "adet"}},
+	}
+	assert.NoError(t, req.Validate())
+	assert.Equal(t, "Bir tır battaniye", req.Description)
+	assert.Equal(t, "battaniye", req.Categories[0].Category)
+
+	noLocation := req
+	noLocation.Latitude, noLocation.Longitude = 0, 0
+	assert.ErrorIs(t, noLocation.Validate(), ErrInvalidLocation)
+
+	noCategory := req
+	noCategory.Categories = nil
+	assert.ErrorIs(t, noCategory.Validate(), ErrNoCategory)
+}
+
+func TestUpdateOfferRequestValidate(t *testing.T) {
+	assert.ErrorIs(t, (&UpdateOfferRequest{}).Validate(), ErrEmptyUpdate)
+
+	lat := 37.0
+	assert.ErrorIs(t, (&UpdateOfferRequest{Latitude: &lat}).Validate(), ErrInvalidLocation)
+	assert.ErrorIs(t, (&UpdateOfferRequest{Categories: []needs.Category{}}).Validate(), ErrNoCategory)
+
+	inactive := false
+	assert.NoError(t, (&UpdateOfferRequest{IsActive: &inactive}).Validate())
+}
+
+func TestNewResponse(t *testing.T) {
+	now := time.Now()
+	results := []Offer{{ID: 3, CreatedAt: now}, {ID: 2, CreatedAt: now.Add(-time.Minute)}, {ID: 1, CreatedAt: now.Add(-time.Hour)}}
+
+	resp := NewResponse(results, 2)
+	assert.Equal(t, 2, resp.Count)
+	assert.Equal(t, needs.Cursor{Timestamp: now.Add(-time.Minute), ID: 2}.Encode(), resp.NextCursor)
+
+	resp = NewResponse(results, 3)
+	assert.Empty(t, resp.NextCursor)
+}
@@ -0,0 +1,172 @@
+// Package heatmap builds weighted density grids over a bounding box and renders them as PNG rasters.
+package heatmap
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxCells = 512
+
+	DefaultCells = 128
+)
+
+var ErrInvalidWeights = errors.New("weights must look like reason:weight,reason:weight")
+
+// Spec is the area and resolution of a grid. Cells are counted from the north west corner.
+type Spec struct {
+	SwLat float64 `json:"sw_lat"`
+	SwLng float64 `json:"sw_lng"`
+	NeLat float64 `json:"ne_lat"`
+	NeLng float64 `json:"ne_lng"`
+	Cols  int     `json:"cols"`
+	Rows  int     `json:"rows"`
+}
+
+func (s Spec) Valid() bool {
+	return s.SwLat < s.NeLat && s.SwLng < s.NeLng &&
+		s.SwLat >= -90 && s.NeLat <= 90 && s.SwLng >= -180 && s.NeLng <= 180 &&
+		s.Cols > 0 && s.Cols <= MaxCells && s.Rows > 0 && s.Rows <= MaxCells
+}
+
+type Grid struct {
+	Spec
+	Max float64 `json:"max"`
+	// Cells holds Rows rows of Cols values, north to south and west to east
+	Cells [][]float64 `json:"cells"`
+}
+
+func NewGrid(spec Spec) *Grid {
+	cells := make([][]float64, spec.Rows)
+	for i := range cells {
+		cells[i] = make([]float64, spec.Cols)
+	}
+	return &Grid{Spec: spec, Cells: cells}
+}
+
+// Add adds v to the cell at column x and row y, coordinates outside of the grid are ignored.
+func (g *Grid) Add(x, y int, v float64) {
+	if x < 0 || x >= g.Cols || y < 0 || y >= g.Rows {
+		return
+	}
+
+	g.Cells[y][x] += v
+	if g.Cells[y][x] > g.Max {
+		g.Max = g.Cells[y][x]
+	}
+}
+
+// Weights scales the density contribution of a location by its reasons.
+type Weights map[string]float64
+
+// DefaultWeights favours life threatening reasons over supplies.
+var DefaultWeights = Weights{
+	"enkaz":    5,
+	"kurtarma": 5,
+	"sağlık":   3,
+	"ilaç":     2,
+	"barınma":  2,
+	"çadır":    2,
+	"su":       2,
+	"gıda":     2,
+	"erzak":    2,
+	"ısınma":   1.5,
+	"giyim":    0.5,
+	"giysi":    0.5,
+	"giyecek":  0.5,
+}
+
+// ParseWeights parses "reason:weight" pairs separated by commas on top of base.
+func ParseWeights(s string, base Weights) (Weights, error) {
+	weights := make(Weights, len(base))
+	for reason, weight := range base {
+		weights[reason] = weight
+	}
+
+	if strings.TrimSpace(s) == "" {
+		return weights, nil
+	}
+
+	for _, pair := range strings.Split(s, ",") {
+		reason, weightStr, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, ErrInvalidWeights
+		}
+
+		weight, err := strconv.ParseFloat(strings.TrimSpace(weightStr), 64)
+		if err != nil || weight < 0 {
+			return nil, ErrInvalidWeights
+		}
+
+		weights[strings.TrimSpace(reason)] = weight
+	}
+
+	return weights, nil
+}
+
+// Of returns the weight of a comma separated reason list, the heaviest reason wins.
+// Locations without a known reason weigh 1.
+func (w Weights) Of(reason *string) float64 {
+	if reason == nil {
+		return 1
+	}
+
+	found := false
+	max := 0.0
+	for _, r := range strings.Split(*reason, ",") {
+		if weight, ok := w[strings.TrimSpace(r)]; ok {
+			found = true
+			max = math.Max(max, weight)
+		}
+	}
+
+	if !found {
+		return 1
+	}
+	return max
+}
+
+// Image renders one pixel per cell. Density is scaled by the square root so sparse
+// areas stay visible next to hot spots, empty cells are transparent.
+func (g *Grid) Image() image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, g.Cols, g.Rows))
+	if g.Max == 0 {
+		return img
+	}
+
+	for y, row := range g.Cells {
+		for x, v := range row {
+			if v <= 0 {
+				continue
+			}
+			img.SetNRGBA(x, y, ramp(math.Sqrt(v/g.Max)))
+		}
+	}
+
+	return img
+}
+
+func (g *Grid) WritePNG(w io.Writer) error {
+	return png.Encode(w, g.Image())
+}
+
+// ramp maps t in [0, 1] from translucent blue through yellow to opaque red.
+func ramp(t float64) color.NRGBA {
+	t = math.Max(0, math.Min(1, t))
+	alpha := uint8(64 + 191*t)
+
+	if t < 0.5 {
+		k := t * 2
+		return color.NRGBA{R: uint8(255 * k), G: uint8(255 * k), B: uint8(255 * (1 - k)), A: alpha}
+	}
+
+	k := (t - 0.5) * 2
+	return color.NRGBA{R: 255, G: uint8(255 * (1 - k)), B: 0, A: alpha}
+}
@@ -0,0 +1,64 @@
+package heatmap
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWeightsOf(t *testing.T) {
+	weights := Weights{"enkaz": 5, "giyim": 0.5}
+	enkaz := "giyim,enkaz"
+	giyim := "giyim"
+	unknown := "elektrik"
+
+	assert.Equal(t, 5.0, weights.Of(&enkaz))
+	assert.Equal(t, 0.5, weights.Of(&giyim))
+	assert.Equal(t, 1.0, weights.Of(&unknown))
+	assert.Equal(t, 1.0, weights.Of(nil))
+}
+
+func TestParseWeights(t *testing.T) {
+	weights, err := ParseWeights("su:4, giyim:0", Weights{"su": 2, "enkaz": 5})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Weights{"su": 4, "giyim": 0, "enkaz": 5}, weights)
+
+	_, err = ParseWeights("su", nil)
+	assert.ErrorIs(t, err, ErrInvalidWeights)
+
+	_, err = ParseWeights("su:-1", nil)
+	assert.ErrorIs(t, err, ErrInvalidWeights)
+}
+
+func TestGrid(t *testing.T) {
+	grid := NewGrid(Spec{SwLat: 36, SwLng: 36, NeLat: 37, NeLng: 37, Cols: 2, Rows: 3})
+	grid.Add(1, 2, 2)
+	grid.Add(1, 2, 1)
+	grid.Add(0, 0, 1)
+	grid.Add(5, 5, 100)
+
+	assert.Equal(t, 3.0, grid.Max)
+	assert.Equal(t, [][]float64{{1, 0}, {0, 0}, {0, 3}}, grid.Cells)
+}
+
+func TestWritePNG(t *testing.T) {
+	grid := NewGrid(Spec{SwLat: 36, SwLng: 36, NeLat: 37, NeLng: 37, Cols: 4, Rows: 2})
+	grid.Add(3, 1, 1)
+
+	var buf bytes.Buffer
+	assert.NoError(t, grid.WritePNG(&buf))
+
+	img, err := png.Decode(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, img.Bounds().Dx())
+	assert.Equal(t, 2, img.Bounds().Dy())
+
+	_, _, _, a := img.At(0, 0).RGBA()
+	assert.Equal(t, uint32(0), a)
+	r, _, _, a := img.At(3, 1).RGBA()
+	assert.Equal(t, uint32(0xffff), r)
+	assert.Equal(t, uint32(0xffff), a)
+}
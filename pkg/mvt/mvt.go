@@ -0,0 +1,211 @@
+// Package mvt encodes point layers as Mapbox Vector Tiles (https://github.com/mapbox/vector-tile-spec, version 2).
+// Only what the map needs is implemented: point features with scalar properties.
+package mvt
+
+import (
+	"math"
+	"sort"
+)
+
+const (
+	ContentType = "application/vnd.mapbox-vector-tile"
+
+	DefaultExtent = 4096
+
+	layerVersion  = 2
+	geomTypePoint = 1
+	cmdMoveTo     = 1
+)
+
+// protobuf wire types
+const (
+	wireVarint = 0
+	wire64Bit  = 1
+	wireBytes  = 2
+)
+
+// Feature is a point with properties. Property values must be string, bool, int, int64, float64 or nil.
+type Feature struct {
+	ID         uint64
+	Lat, Lng   float64
+	Properties map[string]interface{}
+}
+
+// Layer collects point features of a single tile.
+type Layer struct {
+	name    string
+	extent  uint32
+	z, x, y int
+
+	features [][]byte
+	keys     []string
+	keyIndex map[string]uint32
+	values   [][]byte
+	valIndex map[interface{}]uint32
+}
+
+func NewLayer(name string, z, x, y int) *Layer {
+	return &Layer{
+		name:     name,
+		extent:   DefaultExtent,
+		z:        z,
+		x:        x,
+		y:        y,
+		keyIndex: make(map[string]uint32),
+		valIndex: make(map[interface{}]uint32),
+	}
+}
+
+func (l *Layer) Len() int {
+	return len(l.features)
+}
+
+func (l *Layer) Add(feature Feature) {
+	keys := make([]string, 0, len(feature.Properties))
+	for key := range feature.Properties {
+		keys = append(keys, key)
+	}
+	// stable tag order keeps tiles byte for byte identical for the same rows
+	sort.Strings(keys)
+
+	var tags []uint64
+	for _, key := range keys {
+		value := feature.Properties[key]
+		valueIdx, ok := l.value(value)
+		if !ok {
+			continue
+		}
+		tags = append(tags, uint64(l.key(key)), uint64(valueIdx))
+	}
+
+	px, py := project(l.z, l.x, l.y, l.extent, feature.Lat, feature.Lng)
+	geometry := []uint64{commandInteger(cmdMoveTo, 1), zigzag(px), zigzag(py)}
+
+	var buf []byte
+	if feature.ID != 0 {
+		buf = appendVarintField(buf, 1, feature.ID)
+	}
+	if len(tags) > 0 {
+		buf = appendPacked(buf, 2, tags)
+	}
+	buf = appendVarintField(buf, 3, geomTypePoint)
+	buf = appendPacked(buf, 4, geometry)
+
+	l.features = append(l.features, buf)
+}
+
+func (l *Layer) key(key string) uint32 {
+	if idx, ok := l.keyIndex[key]; ok {
+		return idx
+	}
+	idx := uint32(len(l.keys))
+	l.keys = append(l.keys, key)
+	l.keyIndex[key] = idx
+	return idx
+}
+
+func (l *Layer) value(value interface{}) (uint32, bool) {
+	if value == nil {
+		return 0, false
+	}
+	if idx, ok := l.valIndex[value]; ok {
+		return idx, true
+	}
+
+	var encoded []byte
+	switch v := value.(type) {
+	case string:
+		encoded = appendBytesField(nil, 1, []byte(v))
+	case float64:
+		encoded = appendTag(nil, 3, wire64Bit)
+		bits := math.Float64bits(v)
+		for i := 0; i < 8; i++ {
+			encoded = append(encoded, byte(bits>>(8*i)))
+		}
+	case int64:
+		encoded = appendVarintField(nil, 6, zigzag(v))
+	case int:
+		encoded = appendVarintField(nil, 6, zigzag(int64(v)))
+	case bool:
+		b := uint64(0)
+		if v {
+			b = 1
+		}
+		encoded = appendVarintField(nil, 7, b)
+	default:
+		return 0, false
+	}
+
+	idx := uint32(len(l.values))
+	l.values = append(l.values, encoded)
+	l.valIndex[value] = idx
+	return idx, true
+}
+
+// Marshal encodes the layers as a vector tile. Empty layers are skipped.
+func Marshal(layers ...*Layer) []byte {
+	var tile []byte
+	for _, l := range layers {
+		if l.Len() == 0 {
+			continue
+		}
+		tile = appendBytesField(tile, 3, l.marshal())
+	}
+	return tile
+}
+
+func (l *Layer) marshal() []byte {
+	var buf []byte
+	buf = appendVarintField(buf, 15, layerVersion)
+	buf = appendBytesField(buf, 1, []byte(l.name))
+	for _, feature := range l.features {
+		buf = appendBytesField(buf, 2, feature)
+	}
+	for _, key := range l.keys {
+		buf = appendBytesField(buf, 3, []byte(key))
+	}
+	for _, value := range l.values {
+		buf = appendBytesField(buf, 4, value)
+	}
+	buf = appendVarintField(buf, 5, uint64(l.extent))
+	return buf
+}
+
+func commandInteger(id, count uint64) uint64 {
+	return (id & 0x7) | (count << 3)
+}
+
+func zigzag(v int64) uint64 {
+	return uint64((v << 1) ^ (v >> 63))
+}
+
+func appendVarint(buf []byte, v uint64) []byte {
+	for v >= 0x80 {
+		buf = append(buf, byte(v)|0x80)
+		v >>= 7
+	}
+	return append(buf, byte(v))
+}
+
+func appendTag(buf []byte, field int, wireType int) []byte {
+	return appendVarint(buf, uint64(field<<3|wireType))
+}
+
+func appendVarintField(buf []byte, field int, v uint64) []byte {
+	buf = appendTag(buf, field, wireVarint)
+	return appendVarint(buf, v)
+}
+
+func appendBytesField(buf []byte, field int, b []byte) []byte {
+	buf = appendTag(buf, field, wireBytes)
+	buf = appendVarint(buf, uint64(len(b)))
+	return append(buf, b...)
+}
+
+func appendPacked(buf []byte, field int, values []uint64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = appendVarint(packed, v)
+	}
+	return appendBytesField(buf, field, packed)
+}
@@ -0,0 +1,66 @@
+package mvt
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZigzag(t *testing.T) {
+	assert.Equal(t, uint64(0), zigzag(0))
+	assert.Equal(t, uint64(1), zigzag(-1))
+	assert.Equal(t, uint64(2), zigzag(1))
+	assert.Equal(t, uint64(4095), zigzag(-2048))
+}
+
+func TestProject(t *testing.T) {
+	x, y := project(0, 0, 0, DefaultExtent, 0, 0)
+	assert.Equal(t, int64(2048), x)
+	assert.Equal(t, int64(2048), y)
+
+	x, y = project(1, 1, 0, DefaultExtent, 0, 0)
+	assert.Equal(t, int64(0), x)
+	assert.Equal(t, int64(4096), y)
+}
+
+func TestTileBounds(t *testing.T) {
+	bounds := TileBounds(1, 1, 0, 0)
+
+	assert.InDelta(t, 0, bounds.MinLat, 1e-9)
+	assert.InDelta(t, 0, bounds.MinLng, 1e-9)
+	assert.InDelta(t, maxMercatorLat, bounds.MaxLat, 1e-6)
+	assert.InDelta(t, 180, bounds.MaxLng, 1e-9)
+}
+
+func TestValidTile(t *testing.T) {
+	assert.True(t, ValidTile(0, 0, 0))
+	assert.True(t, ValidTile(2, 3, 3))
+	assert.False(t, ValidTile(2, 4, 0))
+	assert.False(t, ValidTile(-1, 0, 0))
+	assert.False(t, ValidTile(MaxZoom+1, 0, 0))
+}
+
+func TestMarshal(t *testing.T) {
+	layer := NewLayer("l", 0, 0, 0)
+	layer.Add(Feature{ID: 1, Lat: 0, Lng: 0, Properties: map[string]interface{}{"k": "v"}})
+
+	expected := []byte{
+		0x1a, 0x21, // layer, 33 bytes
+		0x78, 0x02, // version 2
+		0x0a, 0x01, 'l', // name
+		0x12, 0x0f, // feature, 15 bytes
+		0x08, 0x01, // id 1
+		0x12, 0x02, 0x00, 0x00, // tags k=v
+		0x18, 0x01, // point
+		0x22, 0x05, 0x09, 0x80, 0x20, 0x80, 0x20, // MoveTo(2048, 2048)
+		0x1a, 0x01, 'k', // key
+		0x22, 0x03, 0x0a, 0x01, 'v', // value
+		0x28, 0x80, 0x20, // extent 4096
+	}
+
+	assert.Equal(t, expected, Marshal(layer))
+}
+
+func TestMarshalEmpty(t *testing.T) {
+	assert.Empty(t, Marshal(NewLayer("l", 0, 0, 0)))
+}
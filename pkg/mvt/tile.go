@@ -0,0 +1,54 @@
+package mvt
+
+import "math"
+
+const (
+	MaxZoom = 22
+
+	maxMercatorLat = 85.05112878
+)
+
+// Bounds is a geographic bounding box in degrees.
+type Bounds struct {
+	MinLat, MinLng, MaxLat, MaxLng float64
+}
+
+// ValidTile reports whether z/x/y addresses an existing web mercator tile.
+func ValidTile(z, x, y int) bool {
+	if z < 0 || z > MaxZoom {
+		return false
+	}
+	n := 1 << z
+	return x >= 0 && x < n && y >= 0 && y < n
+}
+
+// TileBounds returns the bounding box of tile z/x/y grown by buffer, a fraction of the tile size.
+func TileBounds(z, x, y int, buffer float64) Bounds {
+	n := math.Exp2(float64(z))
+
+	return Bounds{
+		MinLat: math.Max(tileLat(float64(y)+1+buffer, n), -maxMercatorLat),
+		MinLng: math.Max(float64(x)-buffer, 0)/n*360 - 180,
+		MaxLat: math.Min(tileLat(float64(y)-buffer, n), maxMercatorLat),
+		MaxLng: math.Min(float64(x)+1+buffer, n)/n*360 - 180,
+	}
+}
+
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
+
+// project converts a coordinate into the tile coordinate space of z/x/y with the given extent.
+func project(z, x, y int, extent uint32, lat, lng float64) (int64, int64) {
+	n := math.Exp2(float64(z))
+	lat = math.Max(math.Min(lat, maxMercatorLat), -maxMercatorLat)
+	latRad := lat * math.Pi / 180
+
+	worldX := (lng + 180) / 360 * n
+	worldY := (1 - math.Log(math.Tan(latRad)+1/math.Cos(latRad))/math.Pi) / 2 * n
+
+	px := (worldX - float64(x)) * float64(extent)
+	py := (worldY - float64(y)) * float64(extent)
+
+	return int64(math.Round(px)), int64(math.Round(py))
+}
@@ -0,0 +1,143 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/acikkaynak/backend-api-go/search"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+)
+
+const (
+	HeaderName = "X-Location-Backend"
+
+	defaultElasticTimeout = 3 * time.Second
+)
+
+type Backend string
+
+const (
+	BackendPostgres Backend = "postgres"
+	BackendElastic  Backend = "elastic"
+)
+
+var ErrUnknownBackend = errors.New("unknown location backend")
+
+var (
+	readCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "location_reads_total",
+		Help: "Location reads by the backend that served them.",
+	}, []string{"method", "backend", "fallback"})
+)
+
+// LocationReader is a feeds_location read model, implemented by repository.Repository and search.LocationIndex.
+type LocationReader interface {
+	GetLocations(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery) ([]feeds.Location, error)
+	GetLocationClusters(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery, zoom int) ([]feeds.Cluster, error)
+	SearchLocations(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery, text string) ([]feeds.SearchResult, error)
+}
+
+var (
+	_ LocationReader = (*repository.Repository)(nil)
+	_ LocationReader = (*search.LocationIndex)(nil)
+)
+
+// Router sends reads to the configured backend. Elastic reads fall back to postgres when elastic
+// fails or does not answer within the elastic timeout.
+type Router struct {
+	postgres       LocationReader
+	elastic        LocationReader
+	defaultBackend Backend
+	elasticTimeout time.Duration
+}
+
+// NewRouter reads LOCATION_READ_BACKEND (postgres or elastic, postgres by default)
+// and ELASTIC_READ_TIMEOUT (a duration, 3s by default).
+func NewRouter(postgres, elastic LocationReader) *Router {
+	defaultBackend := BackendPostgres
+	if backend, err := ParseBackend(os.Getenv("LOCATION_READ_BACKEND")); err == nil {
+		defaultBackend = backend
+	}
+
+	elasticTimeout := defaultElasticTimeout
+	if timeout, err := time.ParseDuration(os.Getenv("ELASTIC_READ_TIMEOUT")); err == nil && timeout > 0 {
+		elasticTimeout = timeout
+	}
+
+	return &Router{
+		postgres:       postgres,
+		elastic:        elastic,
+		defaultBackend: defaultBackend,
+		elasticTimeout: elasticTimeout,
+	}
+}
+
+// ParseBackend parses a backend name, an empty name selects the configured default.
+func ParseBackend(name string) (Backend, error) {
+	switch Backend(name) {
+	case "":
+		return "", nil
+	case BackendPostgres, BackendElastic:
+		return Backend(name), nil
+	}
+	return "", ErrUnknownBackend
+}
+
+func (r *Router) GetLocations(ctx context.Context, backend Backend, getLocationsQuery *repository.GetLocationsQuery) ([]feeds.Location, Backend, error) {
+	return route(ctx, r, backend, "GetLocations", func(ctx context.Context, reader LocationReader) ([]feeds.Location, error) {
+		return reader.GetLocations(ctx, getLocationsQuery)
+	})
+}
+
+func (r *Router) GetLocationClusters(ctx context.Context, backend Backend, getLocationsQuery *repository.GetLocationsQuery, zoom int) ([]feeds.Cluster, Backend, error) {
+	return route(ctx, r, backend, "GetLocationClusters", func(ctx context.Context, reader LocationReader) ([]feeds.Cluster, error) {
+		return reader.GetLocationClusters(ctx, getLocationsQuery, zoom)
+	})
+}
+
+// SearchLocations prefers elastic regardless of the configured default, postgres full text search
+// is the fallback when elastic is unavailable.
+func (r *Router) SearchLocations(ctx context.Context, backend Backend, getLocationsQuery *repository.GetLocationsQuery, text string) ([]feeds.SearchResult, Backend, error) {
+	if backend == "" {
+		backend = BackendElastic
+	}
+
+	return route(ctx, r, backend, "SearchLocations", func(ctx context.Context, reader LocationReader) ([]feeds.SearchResult, error) {
+		return reader.SearchLocations(ctx, getLocationsQuery, text)
+	})
+}
+
+func route[T any](ctx context.Context, r *Router, backend Backend, method string, read func(context.Context, LocationReader) (T, error)) (T, Backend, error) {
+	if backend == "" {
+		backend = r.defaultBackend
+	}
+
+	if backend == BackendElastic && r.elastic != nil {
+		elasticCtx, cancel := context.WithTimeout(ctx, r.elasticTimeout)
+		data, err := read(elasticCtx, r.elastic)
+		cancel()
+
+		if err == nil {
+			readCounter.WithLabelValues(method, string(BackendElastic), "false").Inc()
+			return data, BackendElastic, nil
+		}
+
+		log.Logger().Warn("elastic location read failed, falling back to postgres",
+			zap.String("method", method), zap.Error(err))
+
+		data, err = read(ctx, r.postgres)
+		readCounter.WithLabelValues(method, string(BackendPostgres), "true").Inc()
+		return data, BackendPostgres, err
+	}
+
+	data, err := read(ctx, r.postgres)
+	readCounter.WithLabelValues(method, string(BackendPostgres), "false").Inc()
+	return data, BackendPostgres, err
+}
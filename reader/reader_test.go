@@ -0,0 +1,119 @@
+package reader
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeReader struct {
+	locations []feeds.Location
+	err       error
+	delay     time.Duration
+}
+
+func (f *fakeReader) GetLocations(ctx context.Context, _ *repository.GetLocationsQuery) ([]feeds.Location, error) {
+	select {
+	case <-time.After(f.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return f.locations, f.err
+}
+
+func (f *fakeReader) GetLocationClusters(ctx context.Context, _ *repository.GetLocationsQuery, _ int) ([]feeds.Cluster, error) {
+	return nil, f.err
+}
+
+func (f *fakeReader) SearchLocations(ctx context.Context, q *repository.GetLocationsQuery, _ string) ([]feeds.SearchResult, error) {
+	locations, err := f.GetLocations(ctx, q)
+	var results []feeds.SearchResult
+	for _, location := range locations {
+		results = append(results, feeds.SearchResult{Location: location})
+	}
+	return results, err
+}
+
+func TestRouterDefaultsToPostgres(t *testing.T) {
+	postgres := &fakeReader{locations: []feeds.Location{{ID: 1}}}
+	elastic := &fakeReader{locations: []feeds.Location{{ID: 2}}}
+	router := &Router{postgres: postgres, elastic: elastic, defaultBackend: BackendPostgres, elasticTimeout: time.Second}
+
+	data, backend, err := router.GetLocations(context.Background(), "", &repository.GetLocationsQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendPostgres, backend)
+	assert.Equal(t, int64(1), data[0].ID)
+}
+
+func TestRouterReadsElastic(t *testing.T) {
+	postgres := &fakeReader{locations: []feeds.Location{{ID: 1}}}
+	elastic := &fakeReader{locations: []feeds.Location{{ID: 2}}}
+	router := &Router{postgres: postgres, elastic: elastic, defaultBackend: BackendPostgres, elasticTimeout: time.Second}
+
+	data, backend, err := router.GetLocations(context.Background(), BackendElastic, &repository.GetLocationsQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendElastic, backend)
+	assert.Equal(t, int64(2), data[0].ID)
+}
+
+func TestRouterFallsBackOnElasticError(t *testing.T) {
+	postgres := &fakeReader{locations: []feeds.Location{{ID: 1}}}
+	elastic := &fakeReader{err: errors.New("cluster unavailable")}
+	router := &Router{postgres: postgres, elastic: elastic, defaultBackend: BackendElastic, elasticTimeout: time.Second}
+
+	data, backend, err := router.GetLocations(context.Background(), "", &repository.GetLocationsQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendPostgres, backend)
+	assert.Equal(t, int64(1), data[0].ID)
+}
+
+func TestRouterFallsBackOnElasticTimeout(t *testing.T) {
+	postgres := &fakeReader{locations: []feeds.Location{{ID: 1}}}
+	elastic := &fakeReader{locations: []feeds.Location{{ID: 2}}, delay: time.Second}
+	router := &Router{postgres: postgres, elastic: elastic, defaultBackend: BackendElastic, elasticTimeout: 10 * time.Millisecond}
+
+	_, backend, err := router.GetLocations(context.Background(), "", &repository.GetLocationsQuery{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendPostgres, backend)
+}
+
+func TestRouterSearchPrefersElastic(t *testing.T) {
+	postgres := &fakeReader{locations: []feeds.Location{{ID: 1}}}
+	elastic := &fakeReader{locations: []feeds.Location{{ID: 2}}}
+	router := &Router{postgres: postgres, elastic: elastic, defaultBackend: BackendPostgres, elasticTimeout: time.Second}
+
+	data, backend, err := router.SearchLocations(context.Background(), "", &repository.GetLocationsQuery{}, "enkaz")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendElastic, backend)
+	assert.Equal(t, int64(2), data[0].ID)
+
+	elastic.err = errors.New("cluster unavailable")
+	data, backend, err = router.SearchLocations(context.Background(), "", &repository.GetLocationsQuery{}, "enkaz")
+
+	assert.NoError(t, err)
+	assert.Equal(t, BackendPostgres, backend)
+	assert.Equal(t, int64(1), data[0].ID)
+}
+
+func TestParseBackend(t *testing.T) {
+	backend, err := ParseBackend("elastic")
+	assert.NoError(t, err)
+	assert.Equal(t, BackendElastic, backend)
+
+	backend, err = ParseBackend("")
+	assert.NoError(t, err)
+	assert.Equal(t, Backend(""), backend)
+
+	_, err = ParseBackend("mongo")
+	assert.ErrorIs(t, err, ErrUnknownBackend)
+}
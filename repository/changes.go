@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+)
+
+// GetLocationChanges returns up to limit+1 locations written after the change token since, ordered by the
+// transaction which wrote them. Only rows of transactions older than every transaction still running are
+// returned: a running transaction commits its rows with a higher transaction id than any returned row,
+// so no change can later appear behind a client's token however long the transaction takes.
+func (repo *Repository) GetLocationChanges(ctx context.Context, since feeds.ChangeToken, limit int) ([]feeds.LocationChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	rawSql, args, err := psql.
+		Select("id",
+			"latitude",
+			"longitude",
+			"entry_id",
+			"epoch",
+			"reason",
+			"channel",
+			"is_location_verified",
+			"is_need_verified",
+			"is_resolved",
+			"needs",
+			"report_count",
+			"is_stale",
+			// duplicates are part of the report_count of their canonical location, clients drop them like deleted ones
+			"is_deleted OR duplicate_of IS NOT NULL",
+			"change_xid",
+			"change_seq").
+		From(feedsLocationTableName).
+		Where("(change_xid, change_seq) > (CAST(? AS bigint), CAST(? AS bigint))", since.XID, since.Seq).
+		Where("change_xid < pg_snapshot_xmin(pg_current_snapshot())::text::bigint").
+		OrderBy("change_xid", "change_seq").
+		Limit(uint64(limit + 1)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query location changes: %w", err)
+	}
+	defer rows.Close()
+
+	var results []feeds.LocationChange
+	for rows.Next() {
+		var change feeds.LocationChange
+		location := &change.Location
+		if err := rows.Scan(&location.ID,
+			&location.Latitude,
+			&location.Longitude,
+			&location.EntryID,
+			&location.Epoch,
+			&location.Reason,
+			&location.Channel,
+			&location.IsLocationVerified,
+			&location.IsNeedVerified,
+			&location.IsResolved,
+			&location.Needs,
+			&location.ReportCount,
+			&location.IsStale,
+			&change.IsDeleted,
+			&change.ChangeXID,
+			&change.ChangeSeq); err != nil {
+			return nil, fmt.Errorf("could not scan location change: %w", err)
+		}
+		location.Loc = []float64{location.Latitude, location.Longitude}
+
+		results = append(results, change)
+	}
+
+	return results, rows.Err()
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+)
+
+// web mercator is undefined at the poles, latitudes are clamped like map tiles do
+const maxMercatorLat = 85.05112878
+
+// GetLocationClusters groups the filtered locations into web mercator tiles of feeds.ClusterPrecision(zoom),
+// the same cells elastic geotile_grid aggregation produces.
+func (repo *Repository) GetLocationClusters(ctx context.Context, getLocationsQuery *GetLocationsQuery, zoom int) ([]feeds.Cluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	precision := feeds.ClusterPrecision(zoom)
+	tiles := math.Exp2(float64(precision))
+	lat := fmt.Sprintf("radians(LEAST(GREATEST(latitude, %f), %f))", -maxMercatorLat, maxMercatorLat)
+
+	selectBuilder := psql.
+		Select().
+		Column("LEAST(floor((longitude + 180) / 360 * ?), ?)::int AS tile_x", tiles, tiles-1).
+		Column("LEAST(floor((1 - ln(tan("+lat+") + 1 / cos("+lat+")) / pi()) / 2 * ?), ?)::int AS tile_y", tiles, tiles-1).
+		Column("reason").
+		Column("count(*)").
+		Column("sum(latitude)").
+		Column("sum(longitude)").
+		From(feedsLocationTableName)
+
+	selectBuilder = applyLocationFilters(selectBuilder, getLocationsQuery).
+		GroupBy("tile_x", "tile_y", "reason")
+
+	rawSql, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query location clusters: %w", err)
+	}
+	defer rows.Close()
+
+	builder := feeds.NewClusterBuilder()
+	for rows.Next() {
+		var (
+			tileX, tileY   int
+			reason         *string
+			count          int
+			sumLat, sumLng float64
+		)
+		if err := rows.Scan(&tileX, &tileY, &reason, &count, &sumLat, &sumLng); err != nil {
+			return nil, fmt.Errorf("could not scan location cluster: %w", err)
+		}
+
+		builder.Add(feeds.TileKey(precision, tileX, tileY), count, sumLat, sumLng, reason)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not read location clusters: %w", err)
+	}
+
+	return builder.Clusters(), nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+// GetDeletedLocations pages through the soft deleted locations, newest first. An empty reason lists all of them,
+// limit+1 rows are returned so the caller can tell whether there is a next page.
+func (repo *Repository) GetDeletedLocations(ctx context.Context, reason string, cursor *feeds.Cursor, limit int) ([]feeds.DeletedLocation, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	selectBuilder := psql.Select("fl.id",
+		"COALESCE(fl.formatted_address, '')",
+		"fl.latitude",
+		"fl.longitude",
+		"fl.entry_id",
+		"COALESCE(fl.epoch, 0)",
+		"fl.reason",
+		"fl.channel",
+		"fe.full_text",
+		"fl.deleted_reason",
+		"fl.deleted_scores",
+		"fl.deleted_at").
+		From(feedsLocationTableName + " AS fl").
+		InnerJoin("feeds_entry AS fe ON fe.id = fl.entry_id").
+		Where(sq.Eq{"fl.is_deleted": true})
+
+	if reason != "" {
+		selectBuilder = selectBuilder.Where(sq.Eq{"fl.deleted_reason": reason})
+	}
+
+	if cursor != nil {
+		selectBuilder = selectBuilder.Where(sq.Lt{"fl.id": cursor.ID})
+	}
+
+	rawSql, args, err := selectBuilder.
+		OrderBy("fl.id DESC").
+		Limit(uint64(limit + 1)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare deleted locations query: %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query deleted locations: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.DeletedLocation, error) {
+		var l feeds.DeletedLocation
+		err := row.Scan(&l.ID,
+			&l.FormattedAddress,
+			&l.Latitude,
+			&l.Longitude,
+			&l.EntryID,
+			&l.Epoch,
+			&l.Reason,
+			&l.Channel,
+			&l.FullText,
+			&l.DeletedReason,
+			&l.DeletedScores,
+			&l.DeletedAt)
+		l.Loc = []float64{l.Latitude, l.Longitude}
+		return l, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan deleted locations: %w", err)
+	}
+
+	return results, nil
+}
+
+// restoreLocationsSql clears is_deleted on the automatically deleted locations of an entry and returns them
+// with the entry text. Restored locations match again, so a restore whose indexing failed can be retried.
+// The deletion reason and scores are kept so restored false positives can be told apart later.
+const restoreLocationsSql = `WITH restored AS (
+	UPDATE feeds_location SET is_deleted = false, restored_by = $2, restored_at = now()
+	WHERE entry_id = $1 AND deleted_reason IS NOT NULL AND (is_deleted = true OR restored_at IS NOT NULL)
+	RETURNING id, COALESCE(formatted_address, '') AS formatted_address, latitude, longitude,
+		northeast_lat, northeast_lng, southwest_lat, southwest_lng, entry_id, COALESCE(epoch, 0) AS epoch,
+		reason, channel, extra_parameters, is_location_verified, is_need_verified, is_resolved, needs
+)
+SELECT restored.*, fe.full_text FROM restored
+INNER JOIN feeds_entry AS fe ON fe.id = restored.entry_id`
+
+// RestoreFeedLocation undoes the soft delete of an entry's locations and returns them with the entry text,
+// so they can be indexed again. It returns ErrFeedNotFound when the entry has no automatically deleted location.
+func (repo *Repository) RestoreFeedLocation(ctx context.Context, entryID int64, restoredBy string) ([]feeds.Location, string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rows, err := repo.pool.Query(ctx, restoreLocationsSql, entryID, restoredBy)
+	if err != nil {
+		return nil, "", fmt.Errorf("could not restore feed location: %w", err)
+	}
+
+	var fullText string
+	locations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.Location, error) {
+		var l feeds.Location
+		err := row.Scan(&l.ID,
+			&l.FormattedAddress,
+			&l.Latitude,
+			&l.Longitude,
+			&l.NortheastLat,
+			&l.NortheastLng,
+			&l.SouthwestLat,
+			&l.SouthwestLng,
+			&l.EntryID,
+			&l.Epoch,
+			&l.Reason,
+			&l.Channel,
+			&l.ExtraParameters,
+			&l.IsLocationVerified,
+			&l.IsNeedVerified,
+			&l.IsResolved,
+			&l.Needs,
+			&fullText)
+		l.Loc = []float64{l.Latitude, l.Longitude}
+		return l, err
+	})
+	if err != nil {
+		return nil, "", fmt.Errorf("could not scan restored feed location: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return nil, "", ErrFeedNotFound
+	}
+
+	return locations, fullText, nil
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrCanonicalNotFound = errors.New("no canonical location found for duplicate")
+
+// canonicalLocationSql picks the oldest listed location of another entry with the same address as the duplicate.
+const canonicalLocationSql = `SELECT c.id FROM feeds_location AS c
+INNER JOIN feeds_location AS d ON d.id = $1
+WHERE lower(c.formatted_address) = lower(d.formatted_address)
+	AND c.entry_id <> d.entry_id
+	AND c.duplicate_of IS NULL AND c.is_deleted IS NOT TRUE
+ORDER BY c.id
+LIMIT 1
+FOR UPDATE OF c`
+
+// AddDuplicate links a location reported as a duplicate to the canonical location of its cluster and
+// returns the cluster id, which is the canonical location id, and the new report count of the cluster.
+// The report refreshes the canonical location, a stale one is current again.
+// ErrCanonicalNotFound is returned when no location of another entry has the same address.
+func (repo *Repository) AddDuplicate(ctx context.Context, locationID int64) (int64, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var clusterID int64
+	if err := tx.QueryRow(ctx, canonicalLocationSql, locationID).Scan(&clusterID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, 0, ErrCanonicalNotFound
+		}
+		return 0, 0, fmt.Errorf("could not query canonical location: %w", err)
+	}
+
+	rawSql, args, err := psql.Update(feedsLocationTableName).
+		Set("duplicate_of", clusterID).
+		Where(sq.Eq{"id": locationID, "duplicate_of": nil}).ToSql()
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not format query : %w", err)
+	}
+
+	tag, err := tx.Exec(ctx, rawSql, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("could not link duplicate location: %w", err)
+	}
+
+	var reportCount int
+	if tag.RowsAffected() > 0 {
+		rawSql, args, err = psql.Update(feedsLocationTableName).
+			Set("report_count", sq.Expr("report_count + 1")).
+			Set("last_reported_at", sq.Expr("now()")).
+			Set("is_stale", false).
+			Set("stale_at", nil).
+			Where(sq.Eq{"id": clusterID}).
+			Suffix("RETURNING report_count").ToSql()
+		if err != nil {
+			return 0, 0, fmt.Errorf("could not format query : %w", err)
+		}
+
+		if err := tx.QueryRow(ctx, rawSql, args...).Scan(&reportCount); err != nil {
+			return 0, 0, fmt.Errorf("could not update cluster report count: %w", err)
+		}
+	} else {
+		// the location was linked before, redelivered messages must not count it again
+		if err := tx.QueryRow(ctx, "SELECT report_count FROM feeds_location WHERE id = $1", clusterID).Scan(&reportCount); err != nil {
+			return 0, 0, fmt.Errorf("could not query cluster report count: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, 0, fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return clusterID, reportCount, nil
+}
+
+// getClusterMembers returns every report of a duplicate cluster, the canonical report first.
+func (repo *Repository) getClusterMembers(ctx context.Context, clusterID int64) ([]feeds.ClusterMember, error) {
+	rawSql, args, err := psql.Select(
+		"fl.id",
+		"fl.entry_id",
+		"fe.full_text",
+		"COALESCE(fe.channel, '')",
+		"COALESCE(fe.epoch, 0)",
+		"COALESCE(fl.formatted_address, '')",
+		"fl.reason").
+		From(feedsLocationTableName+" AS fl").
+		InnerJoin("feeds_entry AS fe ON fe.id = fl.entry_id").
+		Where(sq.Or{sq.Eq{"fl.id": clusterID}, sq.Eq{"fl.duplicate_of": clusterID}}).
+		OrderBy("fl.duplicate_of IS NULL DESC", "fl.id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare select cluster members query: %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query cluster members: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.ClusterMember, error) {
+		var m feeds.ClusterMember
+		err := row.Scan(&m.LocationID, &m.EntryID, &m.FullText, &m.Channel, &m.Epoch, &m.FormattedAddress, &m.Reason)
+		return m, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan cluster members: %w", err)
+	}
+
+	return members, nil
+}
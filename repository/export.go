@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/ggwhite/go-masker"
+)
+
+var (
+	exportContactNameFields  = []string{"isim-soyisim", "name_surname", "name"}
+	exportContactPhoneFields = []string{"tel", "telefon", "numara"}
+)
+
+// ExportLocations runs the GetLocations query with the formatted address of every location and calls fn
+// for every row while iterating the result, like StreamLocations. At most Limit rows are exported when it is set.
+// Contact fields are masked for every channel.
+func (repo *Repository) ExportLocations(ctx context.Context, getLocationsQuery *GetLocationsQuery, fn func(feeds.ExportRow) error) error {
+	query := *getLocationsQuery
+	query.ExtraParams = true
+
+	newSql, args, err := locationsSelect(&query).
+		Column("COALESCE(formatted_address, '')").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, newSql, args...)
+	if err != nil {
+		return fmt.Errorf("could not query locations: %w", err)
+	}
+	defer rows.Close()
+
+	written := 0
+	for rows.Next() {
+		if query.Limit > 0 && written == query.Limit {
+			// the surplus row of locationsSelect only tells there is a next page
+			return nil
+		}
+
+		var row feeds.ExportRow
+		if err := rows.Scan(append(locationDest(&row.Location, &query), &row.FormattedAddress)...); err != nil {
+			return fmt.Errorf("could not scan export row: %w", err)
+		}
+
+		row.Loc = []float64{row.Latitude, row.Longitude}
+		row.ContactName, row.ContactPhone = MaskedContact(row.ExtraParameters)
+		row.ExtraParameters = nil
+
+		if err := fn(row); err != nil {
+			return err
+		}
+		written++
+	}
+
+	return rows.Err()
+}
+
+// MaskedContact returns the masked contact name and phone number found in extra_parameters.
+func MaskedContact(extraParams *string) (name, phone string) {
+	params := parseExtraParameters(extraParams)
+
+	if v := firstParameter(params, exportContactNameFields); v != "" {
+		name = masker.Name(v)
+	}
+	if v := firstParameter(params, exportContactPhoneFields); v != "" {
+		phone = masker.Telephone(v)
+	}
+
+	return name, phone
+}
+
+func firstParameter(params map[string]interface{}, fields []string) string {
+	for _, field := range fields {
+		if v, ok := params[field]; ok && v != nil {
+			if s := fmt.Sprintf("%v", v); s != "" {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// CreateExportLog records who started an export with which format and query string and returns the log id.
+func (repo *Repository) CreateExportLog(ctx context.Context, actor, format, query string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := psql.Insert("feeds_export_log").
+		Columns("actor", "format", "query", "created_at").
+		Values(actor, format, query, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("could not prepare insert export log: %w", err)
+	}
+
+	var id int64
+	if err := repo.pool.QueryRow(ctx, rawSql, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("could not insert export log: %w", err)
+	}
+
+	return id, nil
+}
+
+// CompleteExportLog stores how many rows an export wrote and whether it finished.
+func (repo *Repository) CompleteExportLog(ctx context.Context, id int64, rowCount int, completed bool) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	updateBuilder := psql.Update("feeds_export_log").
+		Set("row_count", rowCount).
+		Where(sq.Eq{"id": id})
+	if completed {
+		updateBuilder = updateBuilder.Set("completed_at", time.Now())
+	}
+
+	rawSql, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare update export log: %w", err)
+	}
+
+	if _, err := repo.pool.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update export log: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/jackc/pgx/v5"
+)
+
+// claimNeedGeocodesSql leases pending needs to a geocoding worker by moving their next attempt into the
+// future, a worker which dies mid batch leaves them to be picked up again once the lease ran out.
+// Needs which already have coordinates are never geocoded again.
+const claimNeedGeocodesSql = `UPDATE needs SET geocode_attempts = geocode_attempts + 1,
+	geocode_next_attempt_at = now() + make_interval(secs => $2::float8 * (geocode_attempts + 1))
+WHERE id IN (
+	SELECT id FROM needs
+	WHERE geocode_status = 'pending' AND latitude = 0 AND longitude = 0
+		AND (geocode_next_attempt_at IS NULL OR geocode_next_attempt_at <= now())
+	ORDER BY id
+	LIMIT $1
+	FOR UPDATE SKIP LOCKED
+)
+RETURNING id, address, geocode_attempts`
+
+// ClaimNeedGeocodes claims up to limit needs waiting for geocoding. A claimed need is retried after
+// retryAfter times its attempts unless its status changes before.
+func (repo *Repository) ClaimNeedGeocodes(ctx context.Context, limit int, retryAfter time.Duration) ([]needs.GeocodeTask, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rows, err := repo.pool.Query(ctx, claimNeedGeocodesSql, limit, retryAfter.Seconds())
+	if err != nil {
+		return nil, fmt.Errorf("could not claim need geocodes: %w", err)
+	}
+
+	tasks, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (needs.GeocodeTask, error) {
+		var task needs.GeocodeTask
+		err := row.Scan(&task.ID, &task.Address, &task.Attempts)
+		return task, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan need geocodes: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// SetNeedLocation stores the geocoded address and coordinates of a need and marks it resolved.
+// Nothing is stored when the need changed since it was claimed, see claimedNeed.
+func (repo *Repository) SetNeedLocation(ctx context.Context, task needs.GeocodeTask, formattedAddress string, latitude, longitude float64) error {
+	return repo.updateNeedGeocode(ctx, psql.Update("needs").
+		Set("formatted_address", formattedAddress).
+		Set("latitude", latitude).
+		Set("longitude", longitude).
+		Set("geocode_status", needs.GeocodeStatusResolved).
+		Set("geocoded_at", time.Now()).
+		Where(claimedNeed(task)))
+}
+
+// SetNeedGeocodeStatus sets the geocoding status of a need, see the needs.GeocodeStatus values.
+// Nothing is stored when the need changed since it was claimed, see claimedNeed.
+func (repo *Repository) SetNeedGeocodeStatus(ctx context.Context, task needs.GeocodeTask, status string) error {
+	return repo.updateNeedGeocode(ctx, psql.Update("needs").
+		Set("geocode_status", status).
+		Set("geocoded_at", time.Now()).
+		Where(claimedNeed(task)))
+}
+
+// claimedNeed matches a need only while it is still waiting for the claimed attempt. Editing the address
+// resets the attempts and a later claim increments them, so the result of an outdated attempt is dropped.
+func claimedNeed(task needs.GeocodeTask) sq.Eq {
+	return sq.Eq{
+		"id":               task.ID,
+		"address":          task.Address,
+		"geocode_status":   needs.GeocodeStatusPending,
+		"geocode_attempts": task.Attempts,
+	}
+}
+
+func (repo *Repository) updateNeedGeocode(ctx context.Context, updateBuilder sq.UpdateBuilder) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	if _, err := repo.pool.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update need geocode: %w", err)
+	}
+
+	return nil
+}
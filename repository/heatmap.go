@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/pkg/heatmap"
+)
+
+// GetDensityGrid sums the filtered locations into the cells of spec. Each location weighs its reason weight,
+// halved every halfLife since its epoch.
+func (repo *Repository) GetDensityGrid(ctx context.Context, getLocationsQuery *GetLocationsQuery, spec heatmap.Spec,
+	weights heatmap.Weights, halfLife time.Duration) (*heatmap.Grid, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	now := time.Now().Unix()
+	cellWidth := (spec.NeLng - spec.SwLng) / float64(spec.Cols)
+	cellHeight := (spec.NeLat - spec.SwLat) / float64(spec.Rows)
+
+	// decay and the cell are computed in postgres, reason weights are applied per group below
+	selectBuilder := psql.
+		Select().
+		Column("LEAST(floor((longitude - ?) / ?), ?)::int AS cell_x", spec.SwLng, cellWidth, spec.Cols-1).
+		Column("LEAST(floor((? - latitude) / ?), ?)::int AS cell_y", spec.NeLat, cellHeight, spec.Rows-1).
+		Column("reason").
+		Column("sum(power(0.5, GREATEST(? - COALESCE(epoch, 0), 0) / ?::float8))", now, halfLife.Seconds()).
+		From(feedsLocationTableName).
+		Where(sq.GtOrEq{"latitude": spec.SwLat, "longitude": spec.SwLng}).
+		Where(sq.LtOrEq{"latitude": spec.NeLat, "longitude": spec.NeLng})
+
+	selectBuilder = applyLocationFilters(selectBuilder, getLocationsQuery).
+		GroupBy("cell_x", "cell_y", "reason")
+
+	rawSql, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query location density: %w", err)
+	}
+	defer rows.Close()
+
+	grid := heatmap.NewGrid(spec)
+	for rows.Next() {
+		var (
+			cellX, cellY int
+			reason       *string
+			density      float64
+		)
+		if err := rows.Scan(&cellX, &cellY, &reason, &density); err != nil {
+			return nil, fmt.Errorf("could not scan location density: %w", err)
+		}
+
+		if math.IsNaN(density) {
+			continue
+		}
+
+		grid.Add(cellX, cellY, density*weights.Of(reason))
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("could not read location density: %w", err)
+	}
+
+	return grid, nil
+}
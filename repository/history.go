@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+const feedsLocationHistoryTableName = "feeds_location_history"
+
+var ErrCorrectionNotFound = errors.New("location correction not found")
+
+// UpdateFeedLocations sets the corrected coordinates and address of every location of the given entries,
+// or only of the given location when LocationID is set, marks them location verified and records each
+// change in feeds_location_history.
+func (repo *Repository) UpdateFeedLocations(ctx context.Context, locations []feeds.FeedLocation, changedBy string) error {
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, location := range locations {
+		correction := feeds.LocationSnapshot{
+			Latitude:           location.Latitude,
+			Longitude:          location.Longitude,
+			FormattedAddress:   location.Address,
+			IsLocationVerified: true,
+		}
+
+		where := sq.Eq{"entry_id": location.EntryID}
+		if location.LocationID != 0 {
+			where["id"] = location.LocationID
+		}
+
+		if _, err := correctLocations(ctx, tx, where, correction,
+			feeds.LocationActionCorrection, changedBy, nil); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return nil
+}
+
+// GetLocationHistory returns the recorded corrections of a feed entry's locations, oldest first.
+func (repo *Repository) GetLocationHistory(ctx context.Context, entryID int64) ([]feeds.LocationCorrection, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := historySelect().
+		Where(sq.Eq{"entry_id": entryID}).
+		OrderBy("changed_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare location history query: %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query location history: %w", err)
+	}
+	defer rows.Close()
+
+	var results []feeds.LocationCorrection
+	for rows.Next() {
+		correction, err := scanCorrection(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan location history: %w", err)
+		}
+		results = append(results, correction)
+	}
+
+	return results, rows.Err()
+}
+
+// RollbackLocation restores the location a correction of the entry changed to what it was before that
+// correction. The rollback is recorded as a correction itself so it can be rolled back again.
+func (repo *Repository) RollbackLocation(ctx context.Context, entryID, correctionID int64, changedBy string) (*feeds.LocationCorrection, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rawSql, args, err := historySelect().
+		Where(sq.Eq{"id": correctionID, "entry_id": entryID}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare location history query: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query location history: %w", err)
+	}
+	target, err := pgx.CollectOneRow(rows, scanCorrection)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrCorrectionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not scan location history: %w", err)
+	}
+
+	corrections, err := correctLocations(ctx, tx, sq.Eq{"id": target.LocationID}, target.Previous,
+		feeds.LocationActionRollback, changedBy, &target.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(corrections) == 0 {
+		return nil, ErrCorrectionNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return &corrections[0], nil
+}
+
+// correctLocations locks the matching locations, applies the correction and writes a history row per location.
+func correctLocations(ctx context.Context, tx pgx.Tx, where sq.Sqlizer, correction feeds.LocationSnapshot,
+	action, changedBy string, rolledBackID *int64) ([]feeds.LocationCorrection, error) {
+	rawSql, args, err := psql.
+		Select("id", "entry_id", "latitude", "longitude", "COALESCE(formatted_address, '')", "is_location_verified").
+		From(feedsLocationTableName).
+		Where(where).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare select feeds location query: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query feeds location: %w", err)
+	}
+
+	now := time.Now()
+	corrections, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.LocationCorrection, error) {
+		c := feeds.LocationCorrection{
+			Action:       action,
+			Current:      correction,
+			ChangedBy:    changedBy,
+			ChangedAt:    now,
+			RolledBackID: rolledBackID,
+		}
+		err := row.Scan(&c.LocationID, &c.EntryID,
+			&c.Previous.Latitude, &c.Previous.Longitude, &c.Previous.FormattedAddress, &c.Previous.IsLocationVerified)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan feeds location: %w", err)
+	}
+
+	for i := range corrections {
+		c := &corrections[i]
+
+		rawSql, args, err := psql.Update(feedsLocationTableName).
+			Set("latitude", c.Current.Latitude).
+			Set("longitude", c.Current.Longitude).
+			Set("formatted_address", c.Current.FormattedAddress).
+			Set("is_location_verified", c.Current.IsLocationVerified).
+			Where(sq.Eq{"id": c.LocationID}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare update feeds location query: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+			return nil, fmt.Errorf("could not update feeds location: %w", err)
+		}
+
+		rawSql, args, err = psql.Insert(feedsLocationHistoryTableName).
+			Columns("location_id", "entry_id", "action",
+				"previous_latitude", "previous_longitude", "previous_formatted_address", "previous_is_location_verified",
+				"latitude", "longitude", "formatted_address", "is_location_verified",
+				"changed_by", "changed_at", "rolled_back_id").
+			Values(c.LocationID, c.EntryID, c.Action,
+				c.Previous.Latitude, c.Previous.Longitude, c.Previous.FormattedAddress, c.Previous.IsLocationVerified,
+				c.Current.Latitude, c.Current.Longitude, c.Current.FormattedAddress, c.Current.IsLocationVerified,
+				c.ChangedBy, c.ChangedAt, c.RolledBackID).
+			Suffix("RETURNING id").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare insert location history query: %w", err)
+		}
+
+		if err := tx.QueryRow(ctx, rawSql, args...).Scan(&c.ID); err != nil {
+			return nil, fmt.Errorf("could not insert location history: %w", err)
+		}
+	}
+
+	return corrections, nil
+}
+
+func historySelect() sq.SelectBuilder {
+	return psql.Select("id", "location_id", "entry_id", "action",
+		"previous_latitude", "previous_longitude", "COALESCE(previous_formatted_address, '')", "previous_is_location_verified",
+		"latitude", "longitude", "COALESCE(formatted_address, '')", "is_location_verified",
+		"changed_by", "changed_at", "rolled_back_id").
+		From(feedsLocationHistoryTableName)
+}
+
+func scanCorrection(row pgx.CollectableRow) (feeds.LocationCorrection, error) {
+	var c feeds.LocationCorrection
+	err := row.Scan(&c.ID, &c.LocationID, &c.EntryID, &c.Action,
+		&c.Previous.Latitude, &c.Previous.Longitude, &c.Previous.FormattedAddress, &c.Previous.IsLocationVerified,
+		&c.Current.Latitude, &c.Current.Longitude, &c.Current.FormattedAddress, &c.Current.IsLocationVerified,
+		&c.ChangedBy, &c.ChangedAt, &c.RolledBackID)
+	return c, err
+}
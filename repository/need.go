@@ -0,0 +1,654 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/jackc/pgx/v5"
+)
+
+const needsHistoryTableName = "needs_history"
+
+func needsSelect() sq.SelectBuilder {
+	return psql.Select("n.id",
+		"n.description",
+		"n.is_resolved",
+		"n.timestamp",
+		"n.extra_parameters",
+		"n.formatted_address",
+		"n.latitude",
+		"n.longitude",
+		"n.geocode_status",
+		"n.address",
+		"n.created_by",
+		"n.updated_by",
+		"n.updated_at",
+		"n.resolved_by",
+		"n.resolved_at",
+		"n.categories").
+		From("needs AS n")
+}
+
+func scanNeed(row pgx.Row) (needs.Need, error) {
+	var n needs.Need
+	n.Loc = make([]float64, 2)
+
+	err := row.Scan(&n.ID,
+		&n.Description,
+		&n.IsResolved,
+		&n.Timestamp,
+		&n.ExtraParameters,
+		&n.FormattedAddress,
+		&n.Loc[0],
+		&n.Loc[1],
+		&n.GeocodeStatus,
+		&n.Address,
+		&n.CreatedBy,
+		&n.UpdatedBy,
+		&n.UpdatedAt,
+		&n.ResolvedBy,
+		&n.ResolvedAt,
+		&n.Categories)
+	return n, err
+}
+
+// GetNeedsQuery filters the needs list, zero values leave a filter out.
+type GetNeedsQuery struct {
+	SwLat, SwLng, NeLat, NeLng float64
+	// Lat, Lng and RadiusM select needs within RadiusM meters of the point
+	Lat, Lng, RadiusM float64
+	// From and To limit the creation time of needs
+	From, To time.Time
+	Text     string
+	// Categories selects needs asking for any of them
+	Categories      []string
+	OnlyNotResolved bool
+	Sort            string
+	Limit           int
+	Cursor          *needs.Cursor
+}
+
+func (q *GetNeedsQuery) HasRadius() bool {
+	return q.RadiusM > 0
+}
+
+// GetNeeds returns a page of needs matching q. Except for distance sorted pages limit+1 rows are returned
+// so the caller can tell whether there is a next page.
+func (repo *Repository) GetNeeds(ctx context.Context, q *GetNeedsQuery) ([]needs.Need, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	selectBuilder := applyNeedFilters(needsSelect(), q)
+
+	switch {
+	case q.Sort == needs.SortDistance && q.HasRadius():
+		// nearest first pages are not cursor paginated, limit only cuts the result
+		selectBuilder = selectBuilder.OrderByClause(distanceExpr+", n.id", q.Lat, q.Lat, q.Lng)
+		if q.Limit > 0 {
+			selectBuilder = selectBuilder.Limit(uint64(q.Limit))
+		}
+		return repo.queryNeeds(ctx, selectBuilder)
+	case q.Sort == needs.SortOldest:
+		if q.Cursor != nil {
+			selectBuilder = selectBuilder.Where("(n.timestamp, n.id) > (?, ?)", q.Cursor.Timestamp, q.Cursor.ID)
+		}
+		selectBuilder = selectBuilder.OrderBy("n.timestamp", "n.id")
+	default:
+		if q.Cursor != nil {
+			selectBuilder = selectBuilder.Where("(n.timestamp, n.id) < (?, ?)", q.Cursor.Timestamp, q.Cursor.ID)
+		}
+		selectBuilder = selectBuilder.OrderBy("n.timestamp DESC", "n.id DESC")
+	}
+
+	if q.Limit > 0 {
+		// one extra row tells the caller whether there is a next page
+		selectBuilder = selectBuilder.Limit(uint64(q.Limit + 1))
+	}
+
+	return repo.queryNeeds(ctx, selectBuilder)
+}
+
+// GetNearbyNeeds returns the open, geocoded needs of any of categories within radiusM meters, nearest first.
+func (repo *Repository) GetNearbyNeeds(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]needs.Need, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	q := &GetNeedsQuery{
+		Lat:             lat,
+		Lng:             lng,
+		RadiusM:         radiusM,
+		Categories:      categories,
+		OnlyNotResolved: true,
+	}
+
+	selectBuilder := applyNeedFilters(needsSelect(), q).
+		Where(sq.Eq{"n.geocode_status": needs.GeocodeStatusResolved}).
+		OrderByClause(distanceExpr+", n.id", lat, lat, lng)
+	if limit > 0 {
+		selectBuilder = selectBuilder.Limit(uint64(limit))
+	}
+
+	return repo.queryNeeds(ctx, selectBuilder)
+}
+
+func (repo *Repository) queryNeeds(ctx context.Context, selectBuilder sq.SelectBuilder) ([]needs.Need, error) {
+	rawSql, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query needs: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (needs.Need, error) {
+		return scanNeed(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan needs: %w", err)
+	}
+
+	return results, nil
+}
+
+// CountNeeds returns how many needs match q regardless of its page.
+func (repo *Repository) CountNeeds(ctx context.Context, q *GetNeedsQuery) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := applyNeedFilters(psql.Select("count(*)").From("needs AS n"), q).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("could not format query : %w", err)
+	}
+
+	var total int
+	if err := repo.pool.QueryRow(ctx, rawSql, args...).Scan(&total); err != nil {
+		return 0, fmt.Errorf("could not count needs: %w", err)
+	}
+
+	return total, nil
+}
+
+func applyNeedFilters(selectBuilder sq.SelectBuilder, q *GetNeedsQuery) sq.SelectBuilder {
+	selectBuilder = selectBuilder.Where(sq.Eq{"n.is_deleted": false})
+
+	if q.OnlyNotResolved {
+		selectBuilder = selectBuilder.Where(sq.Eq{"n.is_resolved": false})
+	}
+
+	if q.SwLat != 0.0 || q.SwLng != 0.0 || q.NeLat != 0.0 || q.NeLng != 0.0 {
+		selectBuilder = selectBuilder.
+			Where(sq.GtOrEq{"n.latitude": q.SwLat, "n.longitude": q.SwLng}).
+			Where(sq.LtOrEq{"n.latitude": q.NeLat, "n.longitude": q.NeLng})
+	}
+
+	if q.HasRadius() {
+		minLat, minLng, maxLat, maxLng := feeds.RadiusBounds(q.Lat, q.Lng, q.RadiusM)
+		selectBuilder = selectBuilder.
+			Where(sq.GtOrEq{"n.latitude": minLat, "n.longitude": minLng}).
+			Where(sq.LtOrEq{"n.latitude": maxLat, "n.longitude": maxLng}).
+			Where(distanceExpr+" <= ?", q.Lat, q.Lat, q.Lng, q.RadiusM)
+	}
+
+	if !q.From.IsZero() {
+		selectBuilder = selectBuilder.Where(sq.GtOrEq{"n.timestamp": q.From})
+	}
+	if !q.To.IsZero() {
+		selectBuilder = selectBuilder.Where(sq.Lt{"n.timestamp": q.To})
+	}
+
+	if q.Text != "" {
+		selectBuilder = selectBuilder.Where("to_tsvector('turkish', n.description) @@ websearch_to_tsquery('turkish', ?)", q.Text)
+	}
+
+	if len(q.Categories) > 0 {
+		selectBuilder = selectBuilder.Where(anyCategory("n.categories", q.Categories))
+	}
+
+	return selectBuilder
+}
+
+// anyCategory matches rows whose categories column holds any of categories. Containment of a single
+// element array is used so the categories index applies.
+func anyCategory(column string, categories []string) sq.Or {
+	or := sq.Or{}
+	for _, category := range categories {
+		contained, _ := json.Marshal([]needs.Category{{Category: category}})
+		or = append(or, sq.Expr(column+" @> CAST(? AS jsonb)", string(contained)))
+	}
+	return or
+}
+
+// GetNeed returns a need which is not deleted or ErrNeedNotFound.
+func (repo *Repository) GetNeed(ctx context.Context, id int64) (*needs.Need, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := needsSelect().
+		Where(sq.Eq{"n.id": id, "n.is_deleted": false}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	need, err := scanNeed(repo.pool.QueryRow(ctx, rawSql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNeedNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not query need: %w", err)
+	}
+
+	return &need, nil
+}
+
+// UpdateNeed applies the fields set in req to a need. Changing the address clears the geocoded location
+// so the geocoding worker resolves the new address.
+func (repo *Repository) UpdateNeed(ctx context.Context, id int64, req needs.UpdateNeedRequest, changedBy string) error {
+	return repo.changeNeed(ctx, id, needs.ActionUpdated, changedBy, func(current needs.Need, now time.Time) (*sq.UpdateBuilder, map[string]interface{}, map[string]interface{}) {
+		updateBuilder := psql.Update("needs").
+			Set("updated_by", changedBy).
+			Set("updated_at", now)
+		previous := map[string]interface{}{}
+		changes := map[string]interface{}{}
+
+		if req.Description != nil && *req.Description != current.Description {
+			updateBuilder = updateBuilder.Set("description", *req.Description)
+			previous["description"] = current.Description
+			changes["description"] = *req.Description
+		}
+
+		if req.Address != nil && *req.Address != current.Address {
+			updateBuilder = updateBuilder.
+				Set("address", *req.Address).
+				Set("normalized_address", needs.NormalizeAddress(*req.Address)).
+				Set("formatted_address", "").
+				Set("latitude", 0).
+				Set("longitude", 0).
+				Set("geocode_status", needs.GeocodeStatusPending).
+				Set("geocode_attempts", 0).
+				Set("geocode_next_attempt_at", nil).
+				Set("geocoded_at", nil)
+			previous["address"] = current.Address
+			changes["address"] = *req.Address
+		}
+
+		if req.ExtraParameters != nil {
+			updateBuilder = updateBuilder.Set("extra_parameters", req.ExtraParameters)
+			if current.ExtraParameters != nil {
+				previous["extra_parameters"] = json.RawMessage(*current.ExtraParameters)
+			}
+			changes["extra_parameters"] = req.ExtraParameters
+		}
+
+		if req.Categories != nil {
+			updateBuilder = updateBuilder.Set("categories", req.Categories)
+			previous["categories"] = current.Categories
+			changes["categories"] = req.Categories
+		}
+
+		if len(changes) == 0 {
+			return nil, nil, nil
+		}
+		return &updateBuilder, previous, changes
+	})
+}
+
+// ResolveNeed closes a need, resolving an already resolved need keeps its original resolution.
+func (repo *Repository) ResolveNeed(ctx context.Context, id int64, resolvedBy string) error {
+	return repo.changeNeed(ctx, id, needs.ActionResolved, resolvedBy, func(current needs.Need, now time.Time) (*sq.UpdateBuilder, map[string]interface{}, map[string]interface{}) {
+		if current.IsResolved {
+			return nil, nil, nil
+		}
+
+		updateBuilder := psql.Update("needs").
+			Set("is_resolved", true).
+			Set("resolved_by", resolvedBy).
+			Set("resolved_at", now)
+		return &updateBuilder, map[string]interface{}{"is_resolved": false}, map[string]interface{}{"is_resolved": true}
+	})
+}
+
+// DeleteNeed soft deletes a need, deleted needs are left out of every read but keep their history.
+func (repo *Repository) DeleteNeed(ctx context.Context, id int64, deletedBy string) error {
+	return repo.changeNeed(ctx, id, needs.ActionDeleted, deletedBy, func(current needs.Need, now time.Time) (*sq.UpdateBuilder, map[string]interface{}, map[string]interface{}) {
+		updateBuilder := psql.Update("needs").
+			Set("is_deleted", true).
+			Set("deleted_by", deletedBy).
+			Set("deleted_at", now)
+		return &updateBuilder, nil, nil
+	})
+}
+
+// needChange builds the update for the current state of a need with the previous and new values of the
+// changed fields, a nil update leaves the need and its history untouched.
+type needChange func(current needs.Need, now time.Time) (*sq.UpdateBuilder, map[string]interface{}, map[string]interface{})
+
+// changeNeed locks a need which is not deleted, applies the update built by change and records it in
+// the need's history. It returns ErrNeedNotFound when there is no such need.
+func (repo *Repository) changeNeed(ctx context.Context, id int64, action, changedBy string, change needChange) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rawSql, args, err := needsSelect().
+		Where(sq.Eq{"n.id": id, "n.is_deleted": false}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	current, err := scanNeed(tx.QueryRow(ctx, rawSql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNeedNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("could not query need: %w", err)
+	}
+
+	now := time.Now()
+	updateBuilder, previous, changes := change(current, now)
+	if updateBuilder == nil {
+		return nil
+	}
+
+	rawSql, args, err = updateBuilder.Where(sq.Eq{"id": id}).ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare update need query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update need: %w", err)
+	}
+
+	if err := insertNeedChange(ctx, tx, needs.Change{
+		NeedID:    id,
+		Action:    action,
+		Previous:  previous,
+		Changes:   changes,
+		ChangedBy: changedBy,
+		ChangedAt: now,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return nil
+}
+
+func insertNeedChange(ctx context.Context, tx pgx.Tx, change needs.Change) error {
+	rawSql, args, err := psql.Insert(needsHistoryTableName).
+		Columns("need_id", "action", "previous", "changes", "changed_by", "changed_at").
+		Values(change.NeedID, change.Action, change.Previous, change.Changes, change.ChangedBy, change.ChangedAt).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare insert need history query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not insert need history: %w", err)
+	}
+
+	return nil
+}
+
+// GetNeedChanges returns the recorded changes of a need and of the needs merged into it, oldest first.
+// Deleted needs keep their history.
+func (repo *Repository) GetNeedChanges(ctx context.Context, id int64) ([]needs.Change, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := psql.Select("id", "need_id", "action", "previous", "changes", "changed_by", "changed_at").
+		From(needsHistoryTableName).
+		Where(sq.Or{
+			sq.Eq{"need_id": id},
+			sq.Expr("need_id IN (SELECT id FROM needs WHERE merged_into = ?)", id),
+		}).
+		OrderBy("changed_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query need history: %w", err)
+	}
+
+	changes, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (needs.Change, error) {
+		var c needs.Change
+		err := row.Scan(&c.ID, &c.NeedID, &c.Action, &c.Previous, &c.Changes, &c.ChangedBy, &c.ChangedAt)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan need history: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetNeedsByAddress returns the needs created since with the same normalized address, they are the
+// candidates a new need at that address could repeat. Deleted and merged needs are left out.
+func (repo *Repository) GetNeedsByAddress(ctx context.Context, normalizedAddress string, since time.Time) ([]needs.Need, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	selectBuilder := needsSelect().
+		Where(sq.Eq{"n.normalized_address": normalizedAddress, "n.is_deleted": false}).
+		Where(sq.GtOrEq{"n.timestamp": since}).
+		OrderBy("n.timestamp DESC", "n.id DESC")
+
+	return repo.queryNeeds(ctx, selectBuilder)
+}
+
+// backfillAddressesSql stores the normalized addresses computed for a batch of needs.
+const backfillAddressesSql = `UPDATE needs AS n SET normalized_address = b.normalized_address
+FROM unnest($1::bigint[], $2::text[]) AS b(id, normalized_address)
+WHERE n.id = b.id AND n.normalized_address IS NULL`
+
+// BackfillNormalizedAddresses fills normalized_address of needs created before it was stored, batchSize needs
+// at a time until none is left or ctx is done, and returns how many needs it filled.
+func (repo *Repository) BackfillNormalizedAddresses(ctx context.Context, batchSize int) (int, error) {
+	var filled int
+	var lastID int64
+	for {
+		ids, addresses, err := repo.getUnnormalizedAddresses(ctx, lastID, batchSize)
+		if err != nil {
+			return filled, err
+		}
+		if len(ids) == 0 {
+			return filled, nil
+		}
+		lastID = ids[len(ids)-1]
+
+		normalized := make([]string, 0, len(addresses))
+		for _, address := range addresses {
+			normalized = append(normalized, needs.NormalizeAddress(address))
+		}
+
+		n, err := repo.setNormalizedAddresses(ctx, ids, normalized)
+		if err != nil {
+			return filled, err
+		}
+		filled += n
+	}
+}
+
+func (repo *Repository) getUnnormalizedAddresses(ctx context.Context, afterID int64, limit int) ([]int64, []string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	rawSql, args, err := psql.Select("id", "COALESCE(address, '')").
+		From("needs").
+		Where(sq.Eq{"normalized_address": nil}).
+		Where(sq.Gt{"id": afterID}).
+		OrderBy("id").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, nil, fmt.Errorf("could not query unnormalized need addresses: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	var addresses []string
+	for rows.Next() {
+		var id int64
+		var address string
+		if err := rows.Scan(&id, &address); err != nil {
+			return nil, nil, fmt.Errorf("could not scan unnormalized need address: %w", err)
+		}
+		ids = append(ids, id)
+		addresses = append(addresses, address)
+	}
+
+	return ids, addresses, rows.Err()
+}
+
+func (repo *Repository) setNormalizedAddresses(ctx context.Context, ids []int64, normalized []string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	tag, err := repo.pool.Exec(ctx, backfillAddressesSql, ids, normalized)
+	if err != nil {
+		return 0, fmt.Errorf("could not store normalized need addresses: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// MergeNeeds merges duplicate needs into the target need. The target takes over the categories of the
+// duplicates, which are deleted and point at the target so its history includes theirs. Needs merged into
+// a duplicate before move on to the target. It returns ErrNeedNotFound when a need is missing or deleted.
+func (repo *Repository) MergeNeeds(ctx context.Context, targetID int64, duplicateIDs []int64, mergedBy string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rawSql, args, err := needsSelect().
+		Where(sq.Eq{"n.id": append([]int64{targetID}, duplicateIDs...), "n.is_deleted": false}).
+		OrderBy("n.id").
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := tx.Query(ctx, rawSql, args...)
+	if err != nil {
+		return fmt.Errorf("could not query needs: %w", err)
+	}
+
+	locked, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (needs.Need, error) {
+		return scanNeed(row)
+	})
+	if err != nil {
+		return fmt.Errorf("could not scan needs: %w", err)
+	}
+	if len(locked) != len(duplicateIDs)+1 {
+		return ErrNeedNotFound
+	}
+
+	// the target's categories come first so they keep their order
+	var target needs.Need
+	categories := [][]needs.Category{nil}
+	for _, n := range locked {
+		if n.ID == targetID {
+			target = n
+			categories[0] = n.Categories
+		} else {
+			categories = append(categories, n.Categories)
+		}
+	}
+	merged := needs.MergeCategories(categories...)
+
+	now := time.Now()
+
+	rawSql, args, err = psql.Update("needs").
+		Set("categories", merged).
+		Set("updated_by", mergedBy).
+		Set("updated_at", now).
+		Where(sq.Eq{"id": targetID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare update need query: %w", err)
+	}
+	if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update need: %w", err)
+	}
+
+	for _, updateBuilder := range []sq.UpdateBuilder{
+		psql.Update("needs").
+			Set("is_deleted", true).
+			Set("deleted_by", mergedBy).
+			Set("deleted_at", now).
+			Set("merged_into", targetID).
+			Where(sq.Eq{"id": duplicateIDs}),
+		psql.Update("needs").
+			Set("merged_into", targetID).
+			Where(sq.Eq{"merged_into": duplicateIDs}),
+	} {
+		rawSql, args, err := updateBuilder.ToSql()
+		if err != nil {
+			return fmt.Errorf("could not prepare merge needs query: %w", err)
+		}
+		if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+			return fmt.Errorf("could not merge needs: %w", err)
+		}
+	}
+
+	changes := []needs.Change{{
+		NeedID:    targetID,
+		Action:    needs.ActionMerged,
+		Previous:  map[string]interface{}{"categories": target.Categories},
+		Changes:   map[string]interface{}{"merged": duplicateIDs, "categories": merged},
+		ChangedBy: mergedBy,
+		ChangedAt: now,
+	}}
+	for _, id := range duplicateIDs {
+		changes = append(changes, needs.Change{
+			NeedID:    id,
+			Action:    needs.ActionMerged,
+			Changes:   map[string]interface{}{"merged_into": targetID},
+			ChangedBy: mergedBy,
+			ChangedAt: now,
+		})
+	}
+	for _, change := range changes {
+		if err := insertNeedChange(ctx, tx, change); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return nil
+}
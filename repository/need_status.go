@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+const feedsLocationNeedHistoryTableName = "feeds_location_need_history"
+
+var ErrNeedNotFound = errors.New("need not found")
+
+// SetNeedStatus updates the status of a need label on every location of a feed entry and records
+// the change per location. It returns ErrNeedNotFound when no location of the entry has the label.
+func (repo *Repository) SetNeedStatus(ctx context.Context, entryID int64, label string, status bool, changedBy string) ([]feeds.NeedStatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rawSql, args, err := psql.Select("id", "needs").
+		From(feedsLocationTableName).
+		Where(sq.Eq{"entry_id": entryID}).
+		Suffix("FOR UPDATE").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare select feeds location needs query: %w", err)
+	}
+
+	rows, err := tx.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query feeds location needs: %w", err)
+	}
+
+	type locationNeeds struct {
+		id    int64
+		needs []feeds.NeedItem
+	}
+	locations, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (locationNeeds, error) {
+		var l locationNeeds
+		err := row.Scan(&l.id, &l.needs)
+		return l, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan feeds location needs: %w", err)
+	}
+
+	now := time.Now()
+	var changes []feeds.NeedStatusChange
+
+	for _, location := range locations {
+		storedLabel, previous, ok := feeds.SetNeedStatus(location.needs, label, status)
+		if !ok {
+			continue
+		}
+
+		rawSql, args, err := psql.Update(feedsLocationTableName).
+			Set("needs", location.needs).
+			Where(sq.Eq{"id": location.id}).
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare update feeds location needs query: %w", err)
+		}
+
+		if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+			return nil, fmt.Errorf("could not update feeds location needs: %w", err)
+		}
+
+		change := feeds.NeedStatusChange{
+			LocationID:     location.id,
+			EntryID:        entryID,
+			Label:          storedLabel,
+			PreviousStatus: previous,
+			Status:         status,
+			ChangedBy:      changedBy,
+			ChangedAt:      now,
+		}
+
+		rawSql, args, err = psql.Insert(feedsLocationNeedHistoryTableName).
+			Columns("location_id", "entry_id", "label", "previous_status", "status", "changed_by", "changed_at").
+			Values(change.LocationID, change.EntryID, change.Label, change.PreviousStatus, change.Status, change.ChangedBy, change.ChangedAt).
+			Suffix("RETURNING id").
+			ToSql()
+		if err != nil {
+			return nil, fmt.Errorf("could not prepare insert need history query: %w", err)
+		}
+
+		if err := tx.QueryRow(ctx, rawSql, args...).Scan(&change.ID); err != nil {
+			return nil, fmt.Errorf("could not insert need history: %w", err)
+		}
+
+		changes = append(changes, change)
+	}
+
+	if len(changes) == 0 {
+		return nil, ErrNeedNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetNeedHistory returns the recorded need status changes of a feed entry, oldest first.
+func (repo *Repository) GetNeedHistory(ctx context.Context, entryID int64) ([]feeds.NeedStatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := psql.
+		Select("id", "location_id", "entry_id", "label", "previous_status", "status", "changed_by", "changed_at").
+		From(feedsLocationNeedHistoryTableName).
+		Where(sq.Eq{"entry_id": entryID}).
+		OrderBy("changed_at", "id").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare need history query: %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query need history: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.NeedStatusChange, error) {
+		var c feeds.NeedStatusChange
+		err := row.Scan(&c.ID, &c.LocationID, &c.EntryID, &c.Label, &c.PreviousStatus, &c.Status, &c.ChangedBy, &c.ChangedAt)
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan need history: %w", err)
+	}
+
+	return results, nil
+}
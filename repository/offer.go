@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/needs"
+	"github.com/acikkaynak/backend-api-go/offers"
+	"github.com/jackc/pgx/v5"
+)
+
+const offersTableName = "offers"
+
+var ErrOfferNotFound = errors.New("offer not found")
+
+// GetOffersQuery filters the offers list, zero values leave a filter out.
+type GetOffersQuery struct {
+	// Categories selects offers of any of them
+	Categories []string
+	OnlyActive bool
+	Limit      int
+	Cursor     *needs.Cursor
+}
+
+func offersSelect() sq.SelectBuilder {
+	return psql.Select("o.id",
+		"o.description",
+		"o.address",
+		"o.latitude",
+		"o.longitude",
+		"o.categories",
+		"o.is_active",
+		"o.created_by",
+		"o.created_at",
+		"o.updated_by",
+		"o.updated_at").
+		From(offersTableName + " AS o")
+}
+
+func scanOffer(row pgx.Row) (offers.Offer, error) {
+	var o offers.Offer
+	o.Loc = make([]float64, 2)
+
+	err := row.Scan(&o.ID,
+		&o.Description,
+		&o.Address,
+		&o.Loc[0],
+		&o.Loc[1],
+		&o.Categories,
+		&o.IsActive,
+		&o.CreatedBy,
+		&o.CreatedAt,
+		&o.UpdatedBy,
+		&o.UpdatedAt)
+	return o, err
+}
+
+func (repo *Repository) CreateOffer(ctx context.Context, req offers.CreateOfferRequest, createdBy string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := psql.Insert(offersTableName).
+		Columns("description", "address", "latitude", "longitude", "categories", "is_active", "created_by", "created_at").
+		Values(req.Description, req.Address, req.Latitude, req.Longitude, req.Categories, true, createdBy, time.Now()).
+		Suffix("RETURNING id").
+		ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("could not format query : %w", err)
+	}
+
+	var id int64
+	if err := repo.pool.QueryRow(ctx, rawSql, args...).Scan(&id); err != nil {
+		return 0, fmt.Errorf("could not insert offer: %w", err)
+	}
+
+	return id, nil
+}
+
+// GetOffer returns an offer or ErrOfferNotFound.
+func (repo *Repository) GetOffer(ctx context.Context, id int64) (*offers.Offer, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := offersSelect().Where(sq.Eq{"o.id": id}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	offer, err := scanOffer(repo.pool.QueryRow(ctx, rawSql, args...))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrOfferNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not query offer: %w", err)
+	}
+
+	return &offer, nil
+}
+
+// GetOffers returns a page of offers matching q newest first, limit+1 rows are returned so the caller
+// can tell whether there is a next page.
+func (repo *Repository) GetOffers(ctx context.Context, q *GetOffersQuery) ([]offers.Offer, error) {
+	selectBuilder := offersSelect()
+
+	if q.OnlyActive {
+		selectBuilder = selectBuilder.Where(sq.Eq{"o.is_active": true})
+	}
+	if len(q.Categories) > 0 {
+		selectBuilder = selectBuilder.Where(anyCategory("o.categories", q.Categories))
+	}
+	if q.Cursor != nil {
+		selectBuilder = selectBuilder.Where("(o.created_at, o.id) < (?, ?)", q.Cursor.Timestamp, q.Cursor.ID)
+	}
+
+	selectBuilder = selectBuilder.OrderBy("o.created_at DESC", "o.id DESC")
+	if q.Limit > 0 {
+		// one extra row tells the caller whether there is a next page
+		selectBuilder = selectBuilder.Limit(uint64(q.Limit + 1))
+	}
+
+	return repo.queryOffers(ctx, selectBuilder)
+}
+
+// GetNearbyOffers returns the active offers of any of categories within radiusM meters, nearest first.
+func (repo *Repository) GetNearbyOffers(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]offers.Offer, error) {
+	minLat, minLng, maxLat, maxLng := feeds.RadiusBounds(lat, lng, radiusM)
+
+	selectBuilder := offersSelect().
+		Where(sq.Eq{"o.is_active": true}).
+		Where(anyCategory("o.categories", categories)).
+		Where(sq.GtOrEq{"o.latitude": minLat, "o.longitude": minLng}).
+		Where(sq.LtOrEq{"o.latitude": maxLat, "o.longitude": maxLng}).
+		Where(distanceExpr+" <= ?", lat, lat, lng, radiusM).
+		OrderByClause(distanceExpr+", o.id", lat, lat, lng)
+	if limit > 0 {
+		selectBuilder = selectBuilder.Limit(uint64(limit))
+	}
+
+	return repo.queryOffers(ctx, selectBuilder)
+}
+
+func (repo *Repository) queryOffers(ctx context.Context, selectBuilder sq.SelectBuilder) ([]offers.Offer, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query offers: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (offers.Offer, error) {
+		return scanOffer(row)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan offers: %w", err)
+	}
+
+	return results, nil
+}
+
+// UpdateOffer applies the fields set in req to an offer, it returns ErrOfferNotFound when there is no such offer.
+func (repo *Repository) UpdateOffer(ctx context.Context, id int64, req offers.UpdateOfferRequest, updatedBy string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	updateBuilder := psql.Update(offersTableName).
+		Set("updated_by", updatedBy).
+		Set("updated_at", time.Now()).
+		Where(sq.Eq{"id": id})
+
+	if req.Description != nil {
+		updateBuilder = updateBuilder.Set("description", *req.Description)
+	}
+	if req.Address != nil {
+		updateBuilder = updateBuilder.Set("address", *req.Address)
+	}
+	if req.Latitude != nil && req.Longitude != nil {
+		updateBuilder = updateBuilder.Set("latitude", *req.Latitude).Set("longitude", *req.Longitude)
+	}
+	if req.Categories != nil {
+		updateBuilder = updateBuilder.Set("categories", req.Categories)
+	}
+	if req.IsActive != nil {
+		updateBuilder = updateBuilder.Set("is_active", *req.IsActive)
+	}
+
+	rawSql, args, err := updateBuilder.ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	tag, err := repo.pool.Exec(ctx, rawSql, args...)
+	if err != nil {
+		return fmt.Errorf("could not update offer: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrOfferNotFound
+	}
+
+	return nil
+}
@@ -44,6 +44,22 @@ type GetLocationsQuery struct {
 	Reason, Channel                    string
 	ExtraParams                        bool
 	IsLocationVerified, IsNeedVerified string
+	IsResolved                         string
+	Limit                              int
+	Cursor                             *feeds.Cursor
+	// Lat, Lng and RadiusM select locations within RadiusM meters of the point
+	Lat, Lng, RadiusM float64
+	// Polygon is a closed ring of [longitude, latitude] pairs
+	Polygon        [][]float64
+	SortByDistance bool
+	// ExcludeSatisfied drops locations whose needs have all been delivered
+	ExcludeSatisfied bool
+	// Stale is one of the feeds.Stale filter values, stale locations are listed when it is empty
+	Stale string
+}
+
+func (q *GetLocationsQuery) HasRadius() bool {
+	return q.RadiusM > 0
 }
 
 type myQueryTracer struct {
@@ -94,10 +110,123 @@ func (repo *Repository) Close() {
 	repo.pool.Close()
 }
 
-func (repo *Repository) GetLocations(getLocationsQuery *GetLocationsQuery) ([]feeds.Location, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*25)
+func (repo *Repository) GetLocations(ctx context.Context, getLocationsQuery *GetLocationsQuery) ([]feeds.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
 	defer cancel()
 
+	newSql, args, err := locationsSelect(getLocationsQuery).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	query, err := repo.pool.Query(ctx, newSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query locations: %w", err)
+	}
+	defer query.Close()
+
+	var results []feeds.Location
+
+	for query.Next() {
+		result, err := scanLocation(query, getLocationsQuery)
+		if err != nil {
+			continue
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// StreamLocations runs the GetLocations query and calls fn for every row while iterating the result,
+// so callers can write rows out without holding the whole result in memory. A paged query stops after
+// Limit rows and returns the cursor of the next page when there is one.
+// Iteration stops at the first error returned by fn or when ctx is done.
+func (repo *Repository) StreamLocations(ctx context.Context, getLocationsQuery *GetLocationsQuery, fn func(feeds.Location) error) (*feeds.Cursor, error) {
+	newSql, args, err := locationsSelect(getLocationsQuery).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, newSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query locations: %w", err)
+	}
+	defer rows.Close()
+
+	var last feeds.Location
+	written := 0
+	for rows.Next() {
+		result, err := scanLocation(rows, getLocationsQuery)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan location: %w", err)
+		}
+
+		if getLocationsQuery.Limit > 0 && written == getLocationsQuery.Limit {
+			// the surplus row of locationsSelect only tells there is a next page
+			return &feeds.Cursor{Epoch: last.Epoch, ID: last.ID}, nil
+		}
+
+		if err := fn(result); err != nil {
+			return nil, err
+		}
+
+		last = result
+		written++
+	}
+
+	return nil, rows.Err()
+}
+
+// GetNearbyFeedLocations returns the open feed locations within radiusM meters asking for any of categories,
+// nearest first. A location asks for a category when its reason contains it or one of its open needs has it as label.
+func (repo *Repository) GetNearbyFeedLocations(ctx context.Context, lat, lng, radiusM float64, categories []string, limit int) ([]feeds.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	getLocationsQuery := &GetLocationsQuery{
+		Lat:            lat,
+		Lng:            lng,
+		RadiusM:        radiusM,
+		IsResolved:     "false",
+		SortByDistance: true,
+		Limit:          limit,
+	}
+
+	reasons := make([]string, 0, len(categories))
+	for _, category := range categories {
+		reasons = append(reasons, "%"+category+"%")
+	}
+
+	newSql, args, err := locationsSelect(getLocationsQuery).
+		Where("(reason ILIKE ANY(?) OR EXISTS (SELECT 1 FROM jsonb_array_elements(COALESCE(needs::jsonb, '[]'::jsonb)) AS item "+
+			"WHERE item->>'status' = 'true' AND lower(item->>'label') = ANY(?)))", reasons, categories).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, newSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query nearby locations: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.Location, error) {
+		var result feeds.Location
+		err := row.Scan(locationDest(&result, getLocationsQuery)...)
+		completeLocation(&result, getLocationsQuery)
+		return result, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan nearby locations: %w", err)
+	}
+
+	return results, nil
+}
+
+// locationsSelect builds the GetLocations query including ordering and paging.
+func locationsSelect(getLocationsQuery *GetLocationsQuery) sq.SelectBuilder {
 	selectBuilder := psql.
 		Select("id",
 			"latitude",
@@ -108,13 +237,98 @@ func (repo *Repository) GetLocations(getLocationsQuery *GetLocationsQuery) ([]fe
 			"channel",
 			"is_location_verified",
 			"is_need_verified",
-			"needs").
+			"is_resolved",
+			"needs",
+			"report_count",
+			"is_stale").
 		From(feedsLocationTableName)
 
 	if getLocationsQuery.ExtraParams == true {
 		selectBuilder = selectBuilder.Column("extra_parameters")
 	}
 
+	if getLocationsQuery.HasRadius() {
+		selectBuilder = selectBuilder.Column(distanceExpr+" AS distance_m",
+			getLocationsQuery.Lat, getLocationsQuery.Lat, getLocationsQuery.Lng)
+	}
+
+	selectBuilder = applyLocationFilters(selectBuilder, getLocationsQuery)
+
+	if getLocationsQuery.SortByDistance && getLocationsQuery.HasRadius() {
+		// nearest first pages are not cursor paginated, limit only cuts the result
+		selectBuilder = selectBuilder.OrderBy("distance_m", "id")
+		if getLocationsQuery.Limit > 0 {
+			selectBuilder = selectBuilder.Limit(uint64(getLocationsQuery.Limit))
+		}
+		return selectBuilder
+	}
+
+	if getLocationsQuery.Cursor != nil {
+		selectBuilder = selectBuilder.Where("(COALESCE(epoch, 0), id) < (?, ?)",
+			getLocationsQuery.Cursor.Epoch, getLocationsQuery.Cursor.ID)
+	}
+
+	if getLocationsQuery.Limit > 0 {
+		// one extra row tells the caller whether there is a next page
+		selectBuilder = selectBuilder.
+			OrderBy("COALESCE(epoch, 0) DESC", "id DESC").
+			Limit(uint64(getLocationsQuery.Limit + 1))
+	}
+
+	return selectBuilder
+}
+
+// scanLocation reads a row of locationsSelect.
+func scanLocation(rows pgx.Rows, getLocationsQuery *GetLocationsQuery) (feeds.Location, error) {
+	var result feeds.Location
+	if err := rows.Scan(locationDest(&result, getLocationsQuery)...); err != nil {
+		return result, err
+	}
+
+	completeLocation(&result, getLocationsQuery)
+
+	return result, nil
+}
+
+// locationDest returns the scan destinations of the locationsSelect columns.
+func locationDest(result *feeds.Location, getLocationsQuery *GetLocationsQuery) []any {
+	dest := []any{&result.ID,
+		&result.Latitude,
+		&result.Longitude,
+		&result.EntryID,
+		&result.Epoch,
+		&result.Reason,
+		&result.Channel,
+		&result.IsLocationVerified,
+		&result.IsNeedVerified,
+		&result.IsResolved,
+		&result.Needs,
+		&result.ReportCount,
+		&result.IsStale,
+	}
+
+	if getLocationsQuery.ExtraParams {
+		dest = append(dest, &result.ExtraParameters)
+	}
+
+	if getLocationsQuery.HasRadius() {
+		dest = append(dest, &result.Distance)
+	}
+
+	return dest
+}
+
+// completeLocation fills the fields derived from the scanned columns.
+func completeLocation(result *feeds.Location, getLocationsQuery *GetLocationsQuery) {
+	result.Loc = []float64{result.Latitude, result.Longitude}
+
+	if getLocationsQuery.ExtraParams && result.Channel != nil {
+		result.ExtraParameters = MaskExtraParameters(*result.Channel, result.ExtraParameters)
+	}
+}
+
+// applyLocationFilters adds the GetLocationsQuery filters shared by every feeds_location read.
+func applyLocationFilters(selectBuilder sq.SelectBuilder, getLocationsQuery *GetLocationsQuery) sq.SelectBuilder {
 	if getLocationsQuery.SwLat != 0.0 || getLocationsQuery.SwLng != 0.0 || getLocationsQuery.NeLat != 0.0 || getLocationsQuery.NeLng != 0.0 {
 		selectBuilder = selectBuilder.Where(sq.GtOrEq{"southwest_lat": getLocationsQuery.SwLat, "southwest_lng": getLocationsQuery.SwLng}).
 			Where(sq.LtOrEq{"northeast_lat": getLocationsQuery.NeLat, "northeast_lng": getLocationsQuery.NeLng})
@@ -155,78 +369,74 @@ func (repo *Repository) GetLocations(getLocationsQuery *GetLocationsQuery) ([]fe
 		selectBuilder = selectBuilder.Where(sq.Eq{"is_need_verified": getLocationsQuery.IsNeedVerified})
 	}
 
-	selectBuilder = selectBuilder.Where(sq.Eq{"is_deleted": false})
-
-	newSql, args, err := selectBuilder.ToSql()
-	if err != nil {
-		return nil, fmt.Errorf("could not format query : %w", err)
+	if getLocationsQuery.IsResolved != "" {
+		isResolved, err := strconv.ParseBool(getLocationsQuery.IsResolved)
+		if err == nil {
+			selectBuilder = selectBuilder.Where(sq.Eq{"is_resolved": isResolved})
+		}
 	}
 
-	query, err := repo.pool.Query(ctx, newSql, args...)
-	if err != nil {
-		return nil, fmt.Errorf("could not query locations: %w", err)
+	if getLocationsQuery.ExcludeSatisfied {
+		selectBuilder = selectBuilder.Where("(needs IS NULL OR "+
+			"NOT jsonb_path_exists(needs::jsonb, CAST(? AS jsonpath)) OR jsonb_path_exists(needs::jsonb, CAST(? AS jsonpath)))",
+			"$[*] ? (@.status == false)", "$[*] ? (@.status == true)")
 	}
 
-	var results []feeds.Location
+	if getLocationsQuery.HasRadius() {
+		minLat, minLng, maxLat, maxLng := feeds.RadiusBounds(getLocationsQuery.Lat, getLocationsQuery.Lng, getLocationsQuery.RadiusM)
+		selectBuilder = selectBuilder.
+			Where(sq.GtOrEq{"latitude": minLat, "longitude": minLng}).
+			Where(sq.LtOrEq{"latitude": maxLat, "longitude": maxLng}).
+			Where(distanceExpr+" <= ?",
+				getLocationsQuery.Lat, getLocationsQuery.Lat, getLocationsQuery.Lng, getLocationsQuery.RadiusM)
+	}
 
-	for query.Next() {
-		var result feeds.Location
-		if getLocationsQuery.ExtraParams {
-			err := query.Scan(&result.ID,
-				&result.Latitude,
-				&result.Longitude,
-				&result.EntryID,
-				&result.Epoch,
-				&result.Reason,
-				&result.Channel,
-				&result.IsLocationVerified,
-				&result.IsNeedVerified,
-				&result.Needs,
-				&result.ExtraParameters,
-			)
-			if err != nil {
-				continue
-			}
-
-			result.Loc = []float64{result.Latitude, result.Longitude}
-
-			if *result.Channel == "twitter" || *result.Channel == "discord" || *result.Channel == "babala" {
-				result.ExtraParameters = maskFields(result.ExtraParameters)
-			}
-		} else {
-			err := query.Scan(&result.ID,
-				&result.Latitude,
-				&result.Longitude,
-				&result.EntryID,
-				&result.Epoch,
-				&result.Reason,
-				&result.Channel,
-				&result.IsLocationVerified,
-				&result.IsNeedVerified,
-				&result.Needs)
-			if err != nil {
-				continue
-			}
-			result.Loc = []float64{result.Latitude, result.Longitude}
-		}
+	if len(getLocationsQuery.Polygon) > 0 {
+		minLat, minLng, maxLat, maxLng := feeds.PolygonBounds(getLocationsQuery.Polygon)
+		selectBuilder = selectBuilder.
+			Where(sq.GtOrEq{"latitude": minLat, "longitude": minLng}).
+			Where(sq.LtOrEq{"latitude": maxLat, "longitude": maxLng}).
+			Where("CAST(? AS text)::polygon @> point(longitude, latitude)", polygonLiteral(getLocationsQuery.Polygon))
+	}
 
-		results = append(results, result)
+	switch getLocationsQuery.Stale {
+	case feeds.StaleExclude:
+		selectBuilder = selectBuilder.Where(sq.Eq{"is_stale": false})
+	case feeds.StaleOnly:
+		selectBuilder = selectBuilder.Where(sq.Eq{"is_stale": true})
 	}
 
-	return results, nil
+	// duplicates are counted in the report_count of their canonical location and not listed on their own
+	selectBuilder = selectBuilder.Where(sq.Eq{"is_deleted": false, "duplicate_of": nil})
+
+	return selectBuilder
 }
 
-func maskFields(extraParams *string) *string {
-	if extraParams == nil || *extraParams == "" {
-		return nil
+// distanceExpr is the haversine distance in meters between a row and a point, its arguments are lat, lat, lng.
+var distanceExpr = fmt.Sprintf("(%f * 2 * asin(LEAST(1, sqrt("+
+	"power(sin(radians(latitude - ?) / 2), 2) + "+
+	"cos(radians(?)) * cos(radians(latitude)) * power(sin(radians(longitude - ?) / 2), 2)))))", feeds.EarthRadiusM)
+
+// polygonLiteral formats a ring as a postgres polygon, points are (longitude, latitude) like point(longitude, latitude).
+func polygonLiteral(ring [][]float64) string {
+	points := make([]string, 0, len(ring))
+	for _, p := range ring {
+		points = append(points, "("+strconv.FormatFloat(p[0], 'f', -1, 64)+","+strconv.FormatFloat(p[1], 'f', -1, 64)+")")
 	}
+	return "(" + strings.Join(points, ",") + ")"
+}
 
-	var jsonMap map[string]interface{}
-	extraParamsStr := strings.ReplaceAll(*extraParams, " nan,", "'',")
-	extraParamsStr = strings.ReplaceAll(extraParamsStr, " nan}", "''}")
-	extraParamsStr = strings.ReplaceAll(extraParamsStr, "\\", "")
+// MaskExtraParameters masks personal contact fields of channels that publish them.
+func MaskExtraParameters(channel string, extraParams *string) *string {
+	if channel == "twitter" || channel == "discord" || channel == "babala" {
+		return maskFields(extraParams)
+	}
+	return extraParams
+}
 
-	if err := jsoniter.Unmarshal([]byte(strings.ReplaceAll(extraParamsStr, "'", "\"")), &jsonMap); err != nil {
+func maskFields(extraParams *string) *string {
+	jsonMap := parseExtraParameters(extraParams)
+	if jsonMap == nil {
 		return nil
 	}
 
@@ -241,14 +451,36 @@ func maskFields(extraParams *string) *string {
 	return &s
 }
 
+// parseExtraParameters reads the python dict formatted extra_parameters, nil when they can not be read.
+func parseExtraParameters(extraParams *string) map[string]interface{} {
+	if extraParams == nil || *extraParams == "" {
+		return nil
+	}
+
+	var jsonMap map[string]interface{}
+	extraParamsStr := strings.ReplaceAll(*extraParams, " nan,", "'',")
+	extraParamsStr = strings.ReplaceAll(extraParamsStr, " nan}", "''}")
+	extraParamsStr = strings.ReplaceAll(extraParamsStr, "\\", "")
+
+	if err := jsoniter.Unmarshal([]byte(strings.ReplaceAll(extraParamsStr, "'", "\"")), &jsonMap); err != nil {
+		return nil
+	}
+
+	return jsonMap
+}
+
 func (repo *Repository) GetFeed(id int64) (*feeds.Feed, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
 	defer cancel()
 
+	// id is an entry id or, as the map lists every location of an entry, the id of one of its locations.
+	// Secondary location ids are drawn from the entry sequence, so they never name another entry.
+	// formatted_address, reason and coordinates of the feed come from the requested location or else from
+	// the first one, the one sharing its id with the entry, all locations are listed in Locations
 	rawSql, args, err := psql.Select(
 		"fe.id",
-		"full_text",
-		"is_resolved",
+		"fe.full_text",
+		"fe.is_resolved",
 		"fe.channel",
 		"fe.timestamp",
 		"fe.epoch",
@@ -256,9 +488,15 @@ func (repo *Repository) GetFeed(id int64) (*feeds.Feed, error) {
 		"fl.formatted_address",
 		"fl.reason",
 		"fl.latitude",
-		"fl.longitude").
-		From("feeds_entry as fe").InnerJoin(feedsLocationTableName + " as fl on fl.entry_id = fe.id").
-		Where(sq.Eq{"fe.id": id}).ToSql()
+		"fl.longitude",
+		"fe.resolved_by",
+		"fe.resolved_at",
+		"fe.resolved_outcome",
+		"COALESCE(fl.duplicate_of, fl.id)").
+		From("feeds_entry as fe").InnerJoin(feedsLocationTableName+" as fl on fl.entry_id = fe.id").
+		Where("fe.id = COALESCE((SELECT id FROM feeds_entry WHERE id = ?), (SELECT entry_id FROM feeds_location WHERE id = ?))", id, id).
+		OrderByClause("fl.id = ? DESC, fl.id = fe.id DESC, fl.id", id).
+		Limit(1).ToSql()
 	if err != nil {
 		return nil, fmt.Errorf("could not prepare select feed query: %w", err)
 	}
@@ -266,6 +504,7 @@ func (repo *Repository) GetFeed(id int64) (*feeds.Feed, error) {
 	row := repo.pool.QueryRow(ctx, rawSql, args...)
 
 	var feed feeds.Feed
+	var clusterID int64
 	if err := row.Scan(
 		&feed.ID,
 		&feed.FullText,
@@ -277,91 +516,185 @@ func (repo *Repository) GetFeed(id int64) (*feeds.Feed, error) {
 		&feed.FormattedAddress,
 		&feed.Reason,
 		&feed.Lat,
-		&feed.Lng); err != nil {
+		&feed.Lng,
+		&feed.ResolvedBy,
+		&feed.ResolvedAt,
+		&feed.ResolvedOutcome,
+		&clusterID); err != nil {
 		return nil, fmt.Errorf("could not query feed with id : %w", err)
 	}
 
-	if feed.Channel == "twitter" || feed.Channel == "discord" || feed.Channel == "babala" {
-		feed.ExtraParameters = maskFields(feed.ExtraParameters)
+	feed.ExtraParameters = MaskExtraParameters(feed.Channel, feed.ExtraParameters)
+
+	feed.Locations, err = repo.getEntryLocations(ctx, feed.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := repo.getClusterMembers(ctx, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) > 1 {
+		feed.ClusterID = &clusterID
+		feed.ReportCount = len(members)
+		feed.ClusterMembers = members
 	}
 
 	return &feed, nil
 }
 
-func (repo *Repository) GetNeeds(onlyNotResolved bool) ([]needs.Need, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
-	defer cancel()
-
-	q := "SELECT n.id, n.description, n.is_resolved, n.timestamp, n.extra_parameters, n.formatted_address, n.latitude, n.longitude " +
-		"FROM needs n"
-	if onlyNotResolved {
-		q = fmt.Sprintf("%s WHERE n.is_resolved=false", q)
-	}
-	query, err := repo.pool.Query(ctx, q)
+// getEntryLocations returns the locations of an entry which are not deleted, first location first.
+func (repo *Repository) getEntryLocations(ctx context.Context, entryID int64) ([]feeds.Location, error) {
+	rawSql, args, err := psql.Select(
+		"id",
+		"formatted_address",
+		"latitude",
+		"longitude",
+		"northeast_lat",
+		"northeast_lng",
+		"southwest_lat",
+		"southwest_lng",
+		"entry_id",
+		"epoch",
+		"reason",
+		"channel",
+		"is_location_verified",
+		"is_need_verified",
+		"is_resolved",
+		"needs",
+		"report_count",
+		"is_stale").
+		From(feedsLocationTableName).
+		Where(sq.Eq{"entry_id": entryID, "is_deleted": false}).
+		OrderBy("id = entry_id DESC", "id").ToSql()
 	if err != nil {
-		return nil, fmt.Errorf("could not query needs: %w", err)
+		return nil, fmt.Errorf("could not prepare select entry locations query: %w", err)
 	}
 
-	var results []needs.Need
-	for query.Next() {
-		var result needs.Need
-		result.Loc = make([]float64, 2)
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query entry locations: %w", err)
+	}
+	defer rows.Close()
 
-		err := query.Scan(&result.ID,
-			&result.Description,
-			&result.IsResolved,
-			&result.Timestamp,
-			&result.ExtraParameters,
+	var results []feeds.Location
+	for rows.Next() {
+		var result feeds.Location
+		if err := rows.Scan(&result.ID,
 			&result.FormattedAddress,
-			&result.Loc[0],
-			&result.Loc[1])
-		if err != nil {
-			continue
+			&result.Latitude,
+			&result.Longitude,
+			&result.NortheastLat,
+			&result.NortheastLng,
+			&result.SouthwestLat,
+			&result.SouthwestLng,
+			&result.EntryID,
+			&result.Epoch,
+			&result.Reason,
+			&result.Channel,
+			&result.IsLocationVerified,
+			&result.IsNeedVerified,
+			&result.IsResolved,
+			&result.Needs,
+			&result.ReportCount,
+			&result.IsStale); err != nil {
+			return nil, fmt.Errorf("could not scan entry location: %w", err)
 		}
+		result.Loc = []float64{result.Latitude, result.Longitude}
 
 		results = append(results, result)
 	}
 
-	return results, nil
+	return results, rows.Err()
 }
 
-func (repo *Repository) CreateNeed(address, description string) (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+// CreateNeed stores a need with its creation in the need's history.
+func (repo *Repository) CreateNeed(ctx context.Context, req needs.CreateNeedRequest, createdBy string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
 	defer cancel()
 
-	q := `INSERT INTO needs(address, description, timestamp, is_resolved, formatted_address, latitude, longitude) VALUES ($1::varchar, $2::varchar, $3::timestamp, $4::bool, $5::varchar, $6::int, $7::int) RETURNING id`
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	categories := req.Categories
+	if categories == nil {
+		categories = []needs.Category{}
+	}
+
+	// the address is resolved to coordinates later by the geocoding worker
+	q := `INSERT INTO needs(address, description, timestamp, is_resolved, formatted_address, latitude, longitude, geocode_status, created_by, categories, normalized_address) VALUES ($1::varchar, $2::varchar, $3::timestamp, $4::bool, $5::varchar, $6::float8, $7::float8, $8::varchar, $9::varchar, $10::jsonb, $11::text) RETURNING id`
 
+	now := time.Now()
 	var id int64
-	err := repo.pool.QueryRow(ctx, q, address, description, time.Now(), false, "", 0, 0).Scan(&id)
+	err = tx.QueryRow(ctx, q, req.Address, req.Description, now, false, "", 0, 0, needs.GeocodeStatusPending, createdBy, categories,
+		needs.NormalizeAddress(req.Address)).Scan(&id)
 	if err != nil {
 		return id, fmt.Errorf("could not query needs: %w", err)
 	}
 
+	if err := insertNeedChange(ctx, tx, needs.Change{
+		NeedID:    id,
+		Action:    needs.ActionCreated,
+		Changes:   map[string]interface{}{"address": req.Address, "description": req.Description, "categories": categories},
+		ChangedBy: createdBy,
+		ChangedAt: now,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("error transaction commit stage %w", err)
+	}
+
 	return id, nil
 }
 
-func (repo *Repository) CreateFeed(ctx context.Context, feed feeds.Feed, location feeds.Location) (error, int64) {
+// CreateFeed stores a feed entry with its locations and returns the entry id and the stored locations.
+// The first location shares its id with the entry like single location entries always did, further
+// locations draw theirs from the entry id sequence so they can never collide with a first location.
+// Locations without an address or coordinates are not stored.
+func (repo *Repository) CreateFeed(ctx context.Context, feed feeds.Feed, locations []feeds.Location) (int64, []feeds.Location, error) {
 	tx, err := repo.pool.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("error transaction begin stage %w", err), 0
+		return 0, nil, fmt.Errorf("error transaction begin stage %w", err)
 	}
 	defer tx.Rollback(ctx)
 
 	entryID, err := repo.createFeedEntry(ctx, tx, feed)
 	if err != nil {
-		return err, 0
+		return 0, nil, err
 	}
 
-	location.EntryID = entryID
-	if _, err = repo.createFeedLocation(ctx, tx, location); err != nil {
-		return err, 0
+	var created []feeds.Location
+	for i, location := range locations {
+		location.EntryID = entryID
+
+		var id interface{} = entryID
+		if i > 0 {
+			id = sq.Expr("nextval('feeds_entry_id_seq')")
+		}
+
+		locationID, err := repo.createFeedLocation(ctx, tx, id, location)
+		if err != nil {
+			return 0, nil, err
+		}
+		if locationID == 0 {
+			continue
+		}
+
+		location.ID = locationID
+		created = append(created, location)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return fmt.Errorf("error transaction commit stage %w", err), 0
+		return 0, nil, fmt.Errorf("error transaction commit stage %w", err)
 	}
 
-	return nil, entryID
+	return entryID, created, nil
 }
 
 func (repo *Repository) createFeedEntry(ctx context.Context, tx pgx.Tx, feed feeds.Feed) (int64, error) {
@@ -388,7 +721,7 @@ func (repo *Repository) createFeedEntry(ctx context.Context, tx pgx.Tx, feed fee
 	return id, nil
 }
 
-func (repo *Repository) createFeedLocation(ctx context.Context, tx pgx.Tx, location feeds.Location) (int64, error) {
+func (repo *Repository) createFeedLocation(ctx context.Context, tx pgx.Tx, id interface{}, location feeds.Location) (int64, error) {
 	rawSql, args, err := psql.Insert(feedsLocationTableName).
 		Columns(
 			"id", "formatted_address",
@@ -398,7 +731,7 @@ func (repo *Repository) createFeedLocation(ctx context.Context, tx pgx.Tx, locat
 			"entry_id",
 			"epoch", "reason", "channel", "extra_parameters").
 		Suffix("RETURNING \"id\"").
-		Values(location.EntryID, location.FormattedAddress,
+		Values(id, location.FormattedAddress,
 			location.Latitude, location.Longitude,
 			location.NortheastLat, location.NortheastLng,
 			location.SouthwestLat, location.SouthwestLng,
@@ -410,22 +743,22 @@ func (repo *Repository) createFeedLocation(ctx context.Context, tx pgx.Tx, locat
 		return 0, fmt.Errorf("could not prepare insert feeds location: %w", err)
 	}
 
-	var id int64
+	var locationID int64
 
 	if location.FormattedAddress != "" && location.Latitude != 0 && location.Longitude != 0 {
-		err := tx.QueryRow(ctx, rawSql, args...).Scan(&id)
+		err := tx.QueryRow(ctx, rawSql, args...).Scan(&locationID)
 		if err != nil {
 			return 0, fmt.Errorf("could not insert feeds location: %w", err)
 		}
 	}
 
-	return id, nil
+	return locationID, nil
 }
 
-func (repo *Repository) UpdateLocationIntentAndNeeds(ctx context.Context, id int64, intents string, needs []feeds.NeedItem) error {
+func (repo *Repository) UpdateLocationIntentAndNeeds(ctx context.Context, locationID int64, intents string, needs []feeds.NeedItem) error {
 	updateBuilder := psql.Update(feedsLocationTableName).
 		Set("reason", intents).
-		Set("needs", needs).Where(sq.Eq{"entry_id": id})
+		Set("needs", needs).Where(sq.Eq{"id": locationID})
 
 	rawSql, args, err := updateBuilder.ToSql()
 	if err != nil {
@@ -439,22 +772,27 @@ func (repo *Repository) UpdateLocationIntentAndNeeds(ctx context.Context, id int
 	return nil
 }
 
-func (repo *Repository) DeleteFeedLocation(ctx context.Context, entryID int64) error {
-	sql, args, err := psql.Update(feedsLocationTableName).Set("is_deleted", true).Where(sq.Eq{"entry_id": entryID}).ToSql()
+// DeleteFeedLocation soft deletes the locations of an entry, reason is one of the feeds.DeletionReason values
+// and scores are the classifier scores the decision was made with.
+func (repo *Repository) DeleteFeedLocation(ctx context.Context, entryID int64, reason string, scores map[string]float64) error {
+	return repo.deleteLocations(ctx, sq.Eq{"entry_id": entryID}, reason, scores)
+}
+
+// DeleteLocation soft deletes a single location of an entry, see DeleteFeedLocation.
+func (repo *Repository) DeleteLocation(ctx context.Context, locationID int64, reason string, scores map[string]float64) error {
+	return repo.deleteLocations(ctx, sq.Eq{"id": locationID}, reason, scores)
+}
+
+func (repo *Repository) deleteLocations(ctx context.Context, where sq.Eq, reason string, scores map[string]float64) error {
+	sql, args, err := psql.Update(feedsLocationTableName).
+		Set("is_deleted", true).
+		Set("deleted_reason", reason).
+		Set("deleted_scores", scores).
+		Set("deleted_at", time.Now()).
+		Where(where).ToSql()
 	if err != nil {
 		return fmt.Errorf("could not prepare soft delete query: %w", err)
 	}
 	_, err = repo.pool.Exec(ctx, sql, args...)
 	return err
 }
-
-func (repo *Repository) UpdateFeedLocations(ctx context.Context, locations []feeds.FeedLocation) error {
-	batch := &pgx.Batch{}
-	for _, location := range locations {
-		batch.Queue(
-			"UPDATE "+feedsLocationTableName+" SET is_location_verified = true, latitude = $1, longitude = $2, formatted_address = $3 WHERE entry_id = $4;",
-			location.Latitude, location.Longitude, location.Address, location.EntryID)
-	}
-	_, err := repo.pool.SendBatch(ctx, batch).Exec()
-	return err
-}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/ggwhite/go-masker"
 	"github.com/stretchr/testify/assert"
@@ -49,3 +50,47 @@ func TestParse(t *testing.T) {
 
 	assert.NoError(t, json.Unmarshal([]byte(str), &jsonMap))
 }
+
+func TestPolygonLiteral(t *testing.T) {
+	ring := [][]float64{{36, 36.5}, {37.25, 36.5}, {37.25, 37}, {36, 36.5}}
+
+	assert.Equal(t, "((36,36.5),(37.25,36.5),(37.25,37),(36,36.5))", polygonLiteral(ring))
+}
+
+func TestMaskedContact(t *testing.T) {
+	extraParams := `{'name_surname': 'Tugay Özalpay', 'tel': '535 555 55 55', 'manual_confirmation': nan}`
+
+	name, phone := MaskedContact(&extraParams)
+
+	assert.Equal(t, masker.Name("Tugay Özalpay"), name)
+	assert.Equal(t, masker.Telephone("535 555 55 55"), phone)
+	assert.NotContains(t, phone, "555 55 55")
+
+	name, phone = MaskedContact(nil)
+	assert.Empty(t, name)
+	assert.Empty(t, phone)
+}
+
+func TestApplyNeedFilters(t *testing.T) {
+	q := &GetNeedsQuery{
+		OnlyNotResolved: true,
+		From:            time.Unix(1675900000, 0),
+		Text:            "battaniye",
+	}
+
+	rawSql, args, err := applyNeedFilters(psql.Select("count(*)").From("needs AS n"), q).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM needs AS n WHERE n.is_deleted = $1 AND n.is_resolved = $2 AND n.timestamp >= $3 "+
+		"AND to_tsvector('turkish', n.description) @@ websearch_to_tsquery('turkish', $4)", rawSql)
+	assert.Equal(t, []interface{}{false, false, q.From, "battaniye"}, args)
+}
+
+func TestApplyNeedFiltersCategories(t *testing.T) {
+	q := &GetNeedsQuery{Categories: []string{"battaniye", "su"}}
+
+	rawSql, args, err := applyNeedFilters(psql.Select("count(*)").From("needs AS n"), q).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT count(*) FROM needs AS n WHERE n.is_deleted = $1 "+
+		"AND (n.categories @> CAST($2 AS jsonb) OR n.categories @> CAST($3 AS jsonb))", rawSql)
+	assert.Equal(t, []interface{}{false, `[{"category":"battaniye"}]`, `[{"category":"su"}]`}, args)
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrFeedNotFound = errors.New("feed not found")
+
+// ResolveFeed marks a feed entry and its locations as handled by resolvedBy with a free text outcome.
+func (repo *Repository) ResolveFeed(ctx context.Context, entryID int64, resolvedBy, outcome string) error {
+	return repo.setFeedResolved(ctx, entryID, psql.Update("feeds_entry").
+		Set("is_resolved", true).
+		Set("resolved_by", resolvedBy).
+		Set("resolved_at", time.Now()).
+		Set("resolved_outcome", outcome))
+}
+
+// ReopenFeed marks a resolved feed entry and its locations as unhandled again and clears its resolution.
+func (repo *Repository) ReopenFeed(ctx context.Context, entryID int64) error {
+	return repo.setFeedResolved(ctx, entryID, psql.Update("feeds_entry").
+		Set("is_resolved", false).
+		Set("resolved_by", nil).
+		Set("resolved_at", nil).
+		Set("resolved_outcome", nil))
+}
+
+// setFeedResolved runs the feeds_entry update and copies its is_resolved to the entry's locations,
+// feeds_location keeps its own copy so location reads can filter on it without a join.
+func (repo *Repository) setFeedResolved(ctx context.Context, entryID int64, entryUpdate sq.UpdateBuilder) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	tx, err := repo.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("error transaction begin stage %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	rawSql, args, err := entryUpdate.
+		Where(sq.Eq{"id": entryID}).
+		Suffix("RETURNING is_resolved").
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare resolve feed query: %w", err)
+	}
+
+	var isResolved bool
+	if err := tx.QueryRow(ctx, rawSql, args...).Scan(&isResolved); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFeedNotFound
+		}
+		return fmt.Errorf("could not update feed resolution: %w", err)
+	}
+
+	rawSql, args, err = psql.Update(feedsLocationTableName).
+		Set("is_resolved", isResolved).
+		Where(sq.Eq{"entry_id": entryID}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare resolve feed locations query: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update feed locations resolution: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("error transaction commit stage %w", err)
+	}
+
+	return nil
+}
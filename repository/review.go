@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+var ErrReviewNotClaimed = errors.New("location is not claimed by the reviewer or the claim expired")
+
+// claimReviewsSql leases the oldest unverified locations to a reviewer, the reviewer's own open claims come first
+// so they are extended before the limit is spent on new rows. Locations claimed by someone else are skipped
+// until their claim expires, SKIP LOCKED keeps concurrent claims from handing out the same rows.
+const claimReviewsSql = `WITH claimed AS (
+	UPDATE feeds_location SET review_claimed_by = $1, review_claimed_until = now() + make_interval(secs => $2)
+	WHERE id IN (
+		SELECT id FROM feeds_location
+		WHERE is_need_verified IS NOT TRUE AND is_deleted IS NOT TRUE AND duplicate_of IS NULL
+			AND (review_claimed_until IS NULL OR review_claimed_until < now() OR review_claimed_by = $1)
+		ORDER BY review_claimed_by IS NOT DISTINCT FROM $1 DESC, COALESCE(epoch, 0), id
+		LIMIT $3
+		FOR UPDATE SKIP LOCKED
+	)
+	RETURNING id, COALESCE(formatted_address, '') AS formatted_address, latitude, longitude, entry_id, epoch,
+		reason, channel, needs, review_claimed_by, review_claimed_until
+)
+SELECT claimed.*, fe.full_text FROM claimed
+INNER JOIN feeds_entry AS fe ON fe.id = claimed.entry_id
+ORDER BY COALESCE(claimed.epoch, 0), claimed.id`
+
+// ClaimReviews claims up to limit locations whose needs are not verified yet for reviewer, for claimTTL.
+// Claiming again returns the reviewer's own open claims first and extends them.
+func (repo *Repository) ClaimReviews(ctx context.Context, reviewer string, limit int, claimTTL time.Duration) ([]feeds.ReviewItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rows, err := repo.pool.Query(ctx, claimReviewsSql, reviewer, claimTTL.Seconds(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("could not claim reviews: %w", err)
+	}
+
+	results, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (feeds.ReviewItem, error) {
+		var item feeds.ReviewItem
+		var epoch *int64
+		err := row.Scan(&item.ID,
+			&item.FormattedAddress,
+			&item.Latitude,
+			&item.Longitude,
+			&item.EntryID,
+			&epoch,
+			&item.Reason,
+			&item.Channel,
+			&item.Needs,
+			&item.ClaimedBy,
+			&item.ClaimedUntil,
+			&item.FullText)
+		if epoch != nil {
+			item.Epoch = *epoch
+		}
+		item.Loc = []float64{item.Latitude, item.Longitude}
+		return item, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not scan claimed reviews: %w", err)
+	}
+
+	return results, nil
+}
+
+// SubmitReview marks the needs of a location claimed by reviewer as verified, replacing the resolved
+// intents and needs with the reviewed ones when they are given, and releases the claim.
+func (repo *Repository) SubmitReview(ctx context.Context, locationID int64, reviewer string, review feeds.SubmitReviewRequest) (*feeds.ReviewResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	updateBuilder := psql.Update(feedsLocationTableName).
+		Set("is_need_verified", true).
+		Set("need_verified_by", reviewer).
+		Set("need_verified_at", time.Now()).
+		Set("review_claimed_by", nil).
+		Set("review_claimed_until", nil)
+
+	if review.Reason != nil {
+		updateBuilder = updateBuilder.Set("reason", *review.Reason)
+	}
+	if review.Needs != nil {
+		updateBuilder = updateBuilder.Set("needs", review.Needs)
+	}
+
+	rawSql, args, err := updateBuilder.
+		Where(sq.Eq{"id": locationID, "review_claimed_by": reviewer}).
+		Where("review_claimed_until >= now()").
+		Suffix("RETURNING id, entry_id, reason, needs, is_need_verified, need_verified_by, need_verified_at").
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not prepare submit review query: %w", err)
+	}
+
+	var result feeds.ReviewResult
+	err = repo.pool.QueryRow(ctx, rawSql, args...).Scan(&result.LocationID,
+		&result.EntryID,
+		&result.Reason,
+		&result.Needs,
+		&result.IsNeedVerified,
+		&result.VerifiedBy,
+		&result.VerifiedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrReviewNotClaimed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not submit review: %w", err)
+	}
+
+	return &result, nil
+}
+
+// ReleaseReview gives a claimed location back to the queue without verifying it.
+func (repo *Repository) ReleaseReview(ctx context.Context, locationID int64, reviewer string) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rawSql, args, err := psql.Update(feedsLocationTableName).
+		Set("review_claimed_by", nil).
+		Set("review_claimed_until", nil).
+		Where(sq.Eq{"id": locationID, "review_claimed_by": reviewer}).
+		ToSql()
+	if err != nil {
+		return fmt.Errorf("could not prepare release review query: %w", err)
+	}
+
+	tag, err := repo.pool.Exec(ctx, rawSql, args...)
+	if err != nil {
+		return fmt.Errorf("could not release review: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrReviewNotClaimed
+	}
+
+	return nil
+}
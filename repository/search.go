@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/acikkaynak/backend-api-go/feeds"
+)
+
+// searchHeadlineOptions wraps matched terms like the elastic highlighter does.
+const searchHeadlineOptions = "StartSel=" + feeds.HighlightPreTag + ", StopSel=" + feeds.HighlightPostTag +
+	", MaxFragments=3, MaxWords=30, MinWords=10, FragmentDelimiter=\" ... \""
+
+// SearchLocations finds the filtered locations whose feed text matches text, best matches first.
+// The text is parsed with websearch_to_tsquery so quoted phrases, "or" and "-" work as in a search box,
+// words are stemmed with the turkish text search configuration.
+func (repo *Repository) SearchLocations(ctx context.Context, getLocationsQuery *GetLocationsQuery, text string) ([]feeds.SearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	// results are ranked, the location filters apply without paging or distance ordering
+	filterQuery := *getLocationsQuery
+	filterQuery.Limit = 0
+	filterQuery.Cursor = nil
+	filterQuery.SortByDistance = false
+
+	limit := getLocationsQuery.Limit
+	if limit <= 0 {
+		limit = feeds.DefaultSearchSize
+	}
+
+	newSql, args, err := psql.
+		Select("fl.*", "ts_headline('turkish', fe.full_text, query, '"+searchHeadlineOptions+"')").
+		FromSelect(locationsSelect(&filterQuery), "fl").
+		InnerJoin("feeds_entry AS fe ON fe.id = fl.entry_id").
+		CrossJoin("websearch_to_tsquery('turkish', ?) AS query", text).
+		Where("to_tsvector('turkish', fe.full_text) @@ query").
+		OrderBy("ts_rank(to_tsvector('turkish', fe.full_text), query) DESC", "fl.id DESC").
+		Limit(uint64(limit)).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, newSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not search locations: %w", err)
+	}
+	defer rows.Close()
+
+	var results []feeds.SearchResult
+
+	for rows.Next() {
+		var result feeds.SearchResult
+		var headline *string
+
+		dest := append(locationDest(&result.Location, &filterQuery), &headline)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("could not scan search result: %w", err)
+		}
+
+		completeLocation(&result.Location, &filterQuery)
+		if headline != nil && *headline != "" {
+			result.Highlights = []string{*headline}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
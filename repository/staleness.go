@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/jackc/pgx/v5"
+)
+
+// markStaleSql flags listed locations whose longest lasting reason was last reported longer ago than the
+// policy allows. Locations without a reason of the policy get a NULL interval and are never flagged.
+const markStaleSql = `UPDATE feeds_location AS fl SET is_stale = true, stale_at = now(), stale_synced_at = NULL
+WHERE fl.is_stale = false AND fl.is_deleted IS NOT TRUE AND fl.is_resolved = false AND fl.duplicate_of IS NULL
+	AND COALESCE(fl.last_reported_at, fl."timestamp", to_timestamp(fl.epoch)) < now() - make_interval(secs => (
+		SELECT max(p.ttl) FROM unnest($1::text[], $2::float8[]) AS p(reason, ttl)
+		WHERE p.reason = ANY(string_to_array(fl.reason, ','))))
+RETURNING fl.id`
+
+// MarkStale flags the locations which expired under policy as stale and returns their ids.
+func (repo *Repository) MarkStale(ctx context.Context, policy feeds.StalenessPolicy) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+	defer cancel()
+
+	reasons, ttls := policy.Reasons()
+	seconds := make([]float64, 0, len(ttls))
+	for _, ttl := range ttls {
+		seconds = append(seconds, ttl.Seconds())
+	}
+
+	rows, err := repo.pool.Query(ctx, markStaleSql, reasons, seconds)
+	if err != nil {
+		return nil, fmt.Errorf("could not mark stale locations: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("could not scan stale locations: %w", err)
+	}
+
+	return ids, nil
+}
+
+// GetUnsyncedStale returns up to limit ids of stale locations whose stale flag has not reached elastic yet.
+func (repo *Repository) GetUnsyncedStale(ctx context.Context, limit int) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	rawSql, args, err := psql.Select("id").
+		From(feedsLocationTableName).
+		Where(sq.Eq{"is_stale": true, "stale_synced_at": nil}).
+		OrderBy("id").
+		Limit(uint64(limit)).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query unsynced stale locations: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("could not scan unsynced stale locations: %w", err)
+	}
+
+	return ids, nil
+}
+
+// SetStaleSynced records that the stale flag of the given locations reached elastic. Locations refreshed
+// in the meantime are left alone.
+func (repo *Repository) SetStaleSynced(ctx context.Context, locationIDs []int64) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	rawSql, args, err := psql.Update(feedsLocationTableName).
+		Set("stale_synced_at", sq.Expr("now()")).
+		Where(sq.Eq{"id": locationIDs, "is_stale": true}).ToSql()
+	if err != nil {
+		return fmt.Errorf("could not format query : %w", err)
+	}
+
+	if _, err := repo.pool.Exec(ctx, rawSql, args...); err != nil {
+		return fmt.Errorf("could not update stale sync: %w", err)
+	}
+
+	return nil
+}
+
+// refreshStaleSql makes the stale listed locations at the address or coordinates of a newly reported
+// location current again.
+const refreshStaleSql = `UPDATE feeds_location AS fl SET last_reported_at = now(), is_stale = false, stale_at = NULL, stale_synced_at = NULL
+FROM feeds_location AS r
+WHERE r.id = $1 AND fl.id <> r.id
+	AND fl.is_stale AND fl.is_deleted IS NOT TRUE AND fl.duplicate_of IS NULL
+	AND ((r.formatted_address <> '' AND lower(fl.formatted_address) = lower(r.formatted_address))
+		OR (fl.latitude = r.latitude AND fl.longitude = r.longitude))
+RETURNING fl.id`
+
+// RefreshStale makes the stale locations reported again by the location with the given id current again
+// and returns their ids.
+func (repo *Repository) RefreshStale(ctx context.Context, locationID int64) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*5)
+	defer cancel()
+
+	rows, err := repo.pool.Query(ctx, refreshStaleSql, locationID)
+	if err != nil {
+		return nil, fmt.Errorf("could not refresh stale locations: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[int64])
+	if err != nil {
+		return nil, fmt.Errorf("could not scan refreshed locations: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/acikkaynak/backend-api-go/feeds"
+	"github.com/acikkaynak/backend-api-go/pkg/mvt"
+)
+
+// MaxTileLocations caps the locations of a tile, low zoom tiles cover whole provinces.
+// The newest locations are drawn, /feeds/clusters aggregates every location instead.
+const MaxTileLocations = 20000
+
+// GetTileLocations returns up to MaxTileLocations filtered locations whose point falls into bounds, newest first.
+// Unlike the bbox filter of GetLocationsQuery, which matches the geocoder bounds, points are matched
+// so that every location is drawn on exactly the tiles covering it.
+func (repo *Repository) GetTileLocations(ctx context.Context, getLocationsQuery *GetLocationsQuery, bounds mvt.Bounds) ([]feeds.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	selectBuilder := psql.
+		Select("id",
+			"latitude",
+			"longitude",
+			"entry_id",
+			"epoch",
+			"reason",
+			"channel",
+			"is_location_verified",
+			"is_need_verified").
+		From(feedsLocationTableName).
+		Where(sq.GtOrEq{"latitude": bounds.MinLat}).
+		Where(sq.LtOrEq{"latitude": bounds.MaxLat}).
+		Where(sq.GtOrEq{"longitude": bounds.MinLng}).
+		Where(sq.LtOrEq{"longitude": bounds.MaxLng})
+
+	selectBuilder = applyLocationFilters(selectBuilder, getLocationsQuery).
+		OrderBy("COALESCE(epoch, 0) DESC", "id DESC").
+		Limit(MaxTileLocations)
+
+	rawSql, args, err := selectBuilder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("could not format query : %w", err)
+	}
+
+	rows, err := repo.pool.Query(ctx, rawSql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("could not query tile locations: %w", err)
+	}
+	defer rows.Close()
+
+	var results []feeds.Location
+	for rows.Next() {
+		var result feeds.Location
+		if err := rows.Scan(&result.ID,
+			&result.Latitude,
+			&result.Longitude,
+			&result.EntryID,
+			&result.Epoch,
+			&result.Reason,
+			&result.Channel,
+			&result.IsLocationVerified,
+			&result.IsNeedVerified); err != nil {
+			return nil, fmt.Errorf("could not scan tile location: %w", err)
+		}
+
+		results = append(results, result)
+	}
+
+	return results, rows.Err()
+}
@@ -32,6 +32,28 @@ ALTER SCHEMA public OWNER TO postgres;
 COMMENT ON SCHEMA public IS 'standard public schema';
 
 
+--
+-- Name: feeds_location_track_change(); Type: FUNCTION; Schema: public; Owner: postgres
+--
+
+CREATE FUNCTION public.feeds_location_track_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    -- recording the elastic stale sync is bookkeeping, not a change clients have to pull
+    IF TG_OP = 'UPDATE' AND to_jsonb(NEW) - 'stale_synced_at' = to_jsonb(OLD) - 'stale_synced_at' THEN
+        RETURN NEW;
+    END IF;
+    NEW.change_seq := nextval('public.feeds_location_change_seq');
+    NEW.change_xid := pg_current_xact_id()::text::bigint;
+    NEW.updated_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$;
+
+
+ALTER FUNCTION public.feeds_location_track_change() OWNER TO postgres;
+
 SET default_tablespace = '';
 
 SET default_table_access_method = heap;
@@ -334,7 +356,10 @@ CREATE TABLE public.feeds_entry (
                                     is_geolocated boolean NOT NULL,
                                     location double precision[],
                                     epoch bigint,
-                                    reason character varying(255)
+                                    reason character varying(255),
+                                    resolved_by character varying(255),
+                                    resolved_at timestamp with time zone,
+                                    resolved_outcome text
 );
 
 
@@ -354,6 +379,37 @@ ALTER TABLE public.feeds_entry ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENT
 );
 
 
+--
+-- Name: feeds_export_log; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.feeds_export_log (
+                                         id bigint NOT NULL,
+                                         actor character varying(255) NOT NULL,
+                                         format character varying(16) NOT NULL,
+                                         query text NOT NULL,
+                                         row_count integer DEFAULT 0 NOT NULL,
+                                         created_at timestamp with time zone NOT NULL,
+                                         completed_at timestamp with time zone
+);
+
+
+ALTER TABLE public.feeds_export_log OWNER TO postgres;
+
+--
+-- Name: feeds_export_log_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+ALTER TABLE public.feeds_export_log ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.feeds_export_log_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
 --
 -- Name: feeds_location; Type: TABLE; Schema: public; Owner: postgres
 --
@@ -370,12 +426,45 @@ CREATE TABLE public.feeds_location (
                                        entry_id bigint NOT NULL,
                                        "timestamp" timestamp with time zone,
                                        epoch bigint,
-                                       reason character varying(255)
+                                       reason character varying(255),
+                                       is_resolved boolean DEFAULT false NOT NULL,
+                                       need_verified_by character varying(255),
+                                       need_verified_at timestamp with time zone,
+                                       review_claimed_by character varying(255),
+                                       review_claimed_until timestamp with time zone,
+                                       deleted_reason character varying(32),
+                                       deleted_scores jsonb,
+                                       deleted_at timestamp with time zone,
+                                       restored_by character varying(255),
+                                       restored_at timestamp with time zone,
+                                       duplicate_of bigint,
+                                       report_count integer DEFAULT 1 NOT NULL,
+                                       last_reported_at timestamp with time zone,
+                                       is_stale boolean DEFAULT false NOT NULL,
+                                       stale_at timestamp with time zone,
+                                       stale_synced_at timestamp with time zone,
+                                       change_seq bigint,
+                                       change_xid bigint,
+                                       updated_at timestamp with time zone
 );
 
 
 ALTER TABLE public.feeds_location OWNER TO postgres;
 
+--
+-- Name: feeds_location_change_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+CREATE SEQUENCE public.feeds_location_change_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+ALTER TABLE public.feeds_location_change_seq OWNER TO postgres;
+
 --
 -- Name: feeds_location_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
 --
@@ -390,6 +479,77 @@ ALTER TABLE public.feeds_location ALTER COLUMN id ADD GENERATED BY DEFAULT AS ID
 );
 
 
+--
+-- Name: feeds_location_history; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.feeds_location_history (
+                                               id bigint NOT NULL,
+                                               location_id bigint NOT NULL,
+                                               entry_id bigint NOT NULL,
+                                               action character varying(32) NOT NULL,
+                                               previous_latitude double precision NOT NULL,
+                                               previous_longitude double precision NOT NULL,
+                                               previous_formatted_address text,
+                                               previous_is_location_verified boolean NOT NULL,
+                                               latitude double precision NOT NULL,
+                                               longitude double precision NOT NULL,
+                                               formatted_address text,
+                                               is_location_verified boolean NOT NULL,
+                                               changed_by character varying(255) NOT NULL,
+                                               changed_at timestamp with time zone NOT NULL,
+                                               rolled_back_id bigint
+);
+
+
+ALTER TABLE public.feeds_location_history OWNER TO postgres;
+
+--
+-- Name: feeds_location_history_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+ALTER TABLE public.feeds_location_history ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.feeds_location_history_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
+--
+-- Name: feeds_location_need_history; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.feeds_location_need_history (
+                                                    id bigint NOT NULL,
+                                                    location_id bigint NOT NULL,
+                                                    entry_id bigint NOT NULL,
+                                                    label character varying(255) NOT NULL,
+                                                    previous_status boolean NOT NULL,
+                                                    status boolean NOT NULL,
+                                                    changed_by character varying(255) NOT NULL,
+                                                    changed_at timestamp with time zone NOT NULL
+);
+
+
+ALTER TABLE public.feeds_location_need_history OWNER TO postgres;
+
+--
+-- Name: feeds_location_need_history_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+ALTER TABLE public.feeds_location_need_history ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.feeds_location_need_history_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
 --
 -- Name: geo_location; Type: TABLE; Schema: public; Owner: postgres
 --
@@ -436,7 +596,22 @@ CREATE TABLE public.needs (
                               extra_parameters jsonb,
                               latitude double precision NOT NULL,
                               longitude double precision NOT NULL,
-                              "timestamp" timestamp with time zone NOT NULL
+                              "timestamp" timestamp with time zone NOT NULL,
+                              geocode_status character varying(16) DEFAULT 'pending'::character varying NOT NULL,
+                              geocode_attempts integer DEFAULT 0 NOT NULL,
+                              geocode_next_attempt_at timestamp with time zone,
+                              geocoded_at timestamp with time zone,
+                              created_by character varying(255),
+                              updated_by character varying(255),
+                              updated_at timestamp with time zone,
+                              resolved_by character varying(255),
+                              resolved_at timestamp with time zone,
+                              is_deleted boolean DEFAULT false NOT NULL,
+                              deleted_by character varying(255),
+                              deleted_at timestamp with time zone,
+                              categories jsonb DEFAULT '[]'::jsonb NOT NULL,
+                              normalized_address text,
+                              merged_into bigint
 );
 
 
@@ -456,6 +631,37 @@ ALTER TABLE public.needs ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
 );
 
 
+--
+-- Name: needs_history; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.needs_history (
+                                      id bigint NOT NULL,
+                                      need_id bigint NOT NULL,
+                                      action character varying(32) NOT NULL,
+                                      previous jsonb,
+                                      changes jsonb,
+                                      changed_by character varying(255) NOT NULL,
+                                      changed_at timestamp with time zone NOT NULL
+);
+
+
+ALTER TABLE public.needs_history OWNER TO postgres;
+
+--
+-- Name: needs_history_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+ALTER TABLE public.needs_history ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.needs_history_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
 --
 -- Name: new_table; Type: TABLE; Schema: public; Owner: postgres
 --
@@ -474,6 +680,41 @@ CREATE TABLE public.new_table (
 
 ALTER TABLE public.new_table OWNER TO postgres;
 
+--
+-- Name: offers; Type: TABLE; Schema: public; Owner: postgres
+--
+
+CREATE TABLE public.offers (
+                               id bigint NOT NULL,
+                               description text NOT NULL,
+                               address text NOT NULL,
+                               latitude double precision NOT NULL,
+                               longitude double precision NOT NULL,
+                               categories jsonb DEFAULT '[]'::jsonb NOT NULL,
+                               is_active boolean DEFAULT true NOT NULL,
+                               created_by character varying(255),
+                               created_at timestamp with time zone NOT NULL,
+                               updated_by character varying(255),
+                               updated_at timestamp with time zone
+);
+
+
+ALTER TABLE public.offers OWNER TO postgres;
+
+--
+-- Name: offers_id_seq; Type: SEQUENCE; Schema: public; Owner: postgres
+--
+
+ALTER TABLE public.offers ALTER COLUMN id ADD GENERATED BY DEFAULT AS IDENTITY (
+    SEQUENCE NAME public.offers_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1
+);
+
+
 --
 -- Name: tweets_depremaddress; Type: TABLE; Schema: public; Owner: postgres
 --
@@ -698,6 +939,14 @@ ALTER TABLE ONLY public.feeds_entry
     ADD CONSTRAINT feeds_entry_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: feeds_export_log feeds_export_log_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.feeds_export_log
+    ADD CONSTRAINT feeds_export_log_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: feeds_location feeds_location_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -706,6 +955,22 @@ ALTER TABLE ONLY public.feeds_location
     ADD CONSTRAINT feeds_location_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: feeds_location_history feeds_location_history_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.feeds_location_history
+    ADD CONSTRAINT feeds_location_history_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: feeds_location_need_history feeds_location_need_history_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.feeds_location_need_history
+    ADD CONSTRAINT feeds_location_need_history_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: geo_location geo_location_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -722,6 +987,22 @@ ALTER TABLE ONLY public.needs
     ADD CONSTRAINT needs_pkey PRIMARY KEY (id);
 
 
+--
+-- Name: needs_history needs_history_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.needs_history
+    ADD CONSTRAINT needs_history_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: offers offers_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
+--
+
+ALTER TABLE ONLY public.offers
+    ADD CONSTRAINT offers_pkey PRIMARY KEY (id);
+
+
 --
 -- Name: tweets_depremaddress_old tweets_depremaddress_pkey; Type: CONSTRAINT; Schema: public; Owner: postgres
 --
@@ -843,6 +1124,13 @@ CREATE INDEX django_session_session_key_c0390e0f_like ON public.django_session U
 CREATE INDEX feeds_entry_epoch_idx ON public.feeds_entry USING btree (epoch);
 
 
+--
+-- Name: feeds_entry_full_text_tsv_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_entry_full_text_tsv_idx ON public.feeds_entry USING gin (to_tsvector('turkish'::regconfig, full_text));
+
+
 --
 -- Name: feeds_location_entry_id_2ef390e3; Type: INDEX; Schema: public; Owner: postgres
 --
@@ -878,6 +1166,111 @@ CREATE INDEX feeds_location_northeast_lat_northeast_lng_southwest_lat_so_idx ON
 CREATE INDEX feeds_location_northeast_lng_idx ON public.feeds_location USING btree (northeast_lng);
 
 
+--
+-- Name: feeds_location_change_xid_change_seq_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_change_xid_change_seq_idx ON public.feeds_location USING btree (change_xid, change_seq);
+
+
+--
+-- Name: feeds_location_deleted_reason_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_deleted_reason_idx ON public.feeds_location USING btree (deleted_reason, id) WHERE is_deleted;
+
+
+--
+-- Name: feeds_location_duplicate_of_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_duplicate_of_idx ON public.feeds_location USING btree (duplicate_of) WHERE (duplicate_of IS NOT NULL);
+
+
+--
+-- Name: feeds_location_stale_address_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_stale_address_idx ON public.feeds_location USING btree (lower(formatted_address), latitude, longitude) WHERE is_stale;
+
+
+--
+-- Name: feeds_location_stale_unsynced_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_stale_unsynced_idx ON public.feeds_location USING btree (id) WHERE (is_stale AND (stale_synced_at IS NULL));
+
+
+--
+-- Name: feeds_location_history_entry_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_history_entry_id_idx ON public.feeds_location_history USING btree (entry_id, changed_at);
+
+
+--
+-- Name: feeds_location_need_history_entry_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX feeds_location_need_history_entry_id_idx ON public.feeds_location_need_history USING btree (entry_id, changed_at);
+
+
+--
+-- Name: needs_categories_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_categories_idx ON public.needs USING gin (categories jsonb_path_ops);
+
+
+--
+-- Name: needs_description_tsv_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_description_tsv_idx ON public.needs USING gin (to_tsvector('turkish'::regconfig, description));
+
+
+--
+-- Name: needs_history_need_id_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_history_need_id_idx ON public.needs_history USING btree (need_id, changed_at);
+
+
+--
+-- Name: needs_merged_into_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_merged_into_idx ON public.needs USING btree (merged_into);
+
+
+--
+-- Name: needs_normalized_address_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_normalized_address_idx ON public.needs USING btree (normalized_address, "timestamp");
+
+
+--
+-- Name: needs_timestamp_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX needs_timestamp_idx ON public.needs USING btree ("timestamp", id);
+
+
+--
+-- Name: offers_categories_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX offers_categories_idx ON public.offers USING gin (categories jsonb_path_ops);
+
+
+--
+-- Name: offers_latitude_longitude_idx; Type: INDEX; Schema: public; Owner: postgres
+--
+
+CREATE INDEX offers_latitude_longitude_idx ON public.offers USING btree (latitude, longitude);
+
+
 --
 -- Name: link; Type: INDEX; Schema: public; Owner: postgres
 --
@@ -885,6 +1278,13 @@ CREATE INDEX feeds_location_northeast_lng_idx ON public.feeds_location USING btr
 CREATE UNIQUE INDEX link ON public.geo_location USING btree (geo_link);
 
 
+--
+-- Name: feeds_location feeds_location_track_change; Type: TRIGGER; Schema: public; Owner: postgres
+--
+
+CREATE TRIGGER feeds_location_track_change BEFORE INSERT OR UPDATE ON public.feeds_location FOR EACH ROW EXECUTE FUNCTION public.feeds_location_track_change();
+
+
 --
 -- Name: auth_group_permissions auth_group_permissio_permission_id_84c5c92e_fk_auth_perm; Type: FK CONSTRAINT; Schema: public; Owner: postgres
 --
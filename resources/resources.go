@@ -0,0 +1,9 @@
+// Package resources embeds the schema files shipped with the service.
+package resources
+
+import _ "embed"
+
+// LocationsIndex holds the settings and mappings of the elastic locations index.
+//
+//go:embed locations_index.json
+var LocationsIndex []byte
@@ -0,0 +1,42 @@
+--
+-- Upgrades a database created before /feeds/changes to track location changes. init.sql already
+-- creates all of this on a fresh database, this script is only run once against existing ones.
+--
+
+BEGIN;
+
+ALTER TABLE public.feeds_location ADD COLUMN IF NOT EXISTS change_seq bigint;
+ALTER TABLE public.feeds_location ADD COLUMN IF NOT EXISTS change_xid bigint;
+ALTER TABLE public.feeds_location ADD COLUMN IF NOT EXISTS updated_at timestamp with time zone;
+
+CREATE SEQUENCE IF NOT EXISTS public.feeds_location_change_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+CREATE OR REPLACE FUNCTION public.feeds_location_track_change() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+    -- recording the elastic stale sync is bookkeeping, not a change clients have to pull
+    IF TG_OP = 'UPDATE' AND to_jsonb(NEW) - 'stale_synced_at' = to_jsonb(OLD) - 'stale_synced_at' THEN
+        RETURN NEW;
+    END IF;
+    NEW.change_seq := nextval('public.feeds_location_change_seq');
+    NEW.change_xid := pg_current_xact_id()::text::bigint;
+    NEW.updated_at := clock_timestamp();
+    RETURN NEW;
+END;
+$$;
+
+DROP TRIGGER IF EXISTS feeds_location_track_change ON public.feeds_location;
+CREATE TRIGGER feeds_location_track_change BEFORE INSERT OR UPDATE ON public.feeds_location FOR EACH ROW EXECUTE FUNCTION public.feeds_location_track_change();
+
+-- locations written before changes were tracked are listed as changed once
+UPDATE public.feeds_location SET change_seq = nextval('public.feeds_location_change_seq'), change_xid = pg_current_xact_id()::text::bigint, updated_at = now() WHERE change_seq IS NULL;
+
+CREATE INDEX IF NOT EXISTS feeds_location_change_xid_change_seq_idx ON public.feeds_location USING btree (change_xid, change_seq);
+
+COMMIT;
@@ -0,0 +1,16 @@
+--
+-- Upgrades a database created before needs were geocoded in the background. init.sql already
+-- creates the columns on a fresh database, this script is only run once against existing ones.
+--
+
+BEGIN;
+
+ALTER TABLE public.needs ADD COLUMN IF NOT EXISTS geocode_status character varying(16) DEFAULT 'pending'::character varying NOT NULL;
+ALTER TABLE public.needs ADD COLUMN IF NOT EXISTS geocode_attempts integer DEFAULT 0 NOT NULL;
+ALTER TABLE public.needs ADD COLUMN IF NOT EXISTS geocode_next_attempt_at timestamp with time zone;
+ALTER TABLE public.needs ADD COLUMN IF NOT EXISTS geocoded_at timestamp with time zone;
+
+-- needs created before geocoding was tracked already carry their coordinates
+UPDATE public.needs SET geocode_status = 'resolved' WHERE geocode_status = 'pending' AND (latitude <> 0 OR longitude <> 0);
+
+COMMIT;
@@ -0,0 +1,13 @@
+--
+-- Upgrades a database created before duplicate needs were detected by address. init.sql already
+-- creates the column on a fresh database, this script is only run once against existing ones.
+-- Addresses are normalized in Go, the api fills normalized_address of existing needs when it starts.
+--
+
+BEGIN;
+
+ALTER TABLE public.needs ADD COLUMN IF NOT EXISTS normalized_address text;
+
+CREATE INDEX IF NOT EXISTS needs_normalized_address_idx ON public.needs USING btree (normalized_address, "timestamp");
+
+COMMIT;
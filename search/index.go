@@ -2,6 +2,7 @@ package search
 
 import (
 	"context"
+	"fmt"
 	"os"
 
 	log "github.com/acikkaynak/backend-api-go/pkg/logger"
@@ -86,6 +87,12 @@ func (i *index[T]) Search(ctx context.Context, query map[string]interface{}) (*R
 
 	fasthttp.ReleaseRequest(req)
 
+	if res.StatusCode() != fasthttp.StatusOK {
+		err := fmt.Errorf("elastic search on %s failed with status %d: %s", i.name, res.StatusCode(), res.Body())
+		fasthttp.ReleaseResponse(res)
+		return nil, err
+	}
+
 	body := res.Body()
 	var response Result[T]
 
@@ -97,3 +104,55 @@ func (i *index[T]) Search(ctx context.Context, query map[string]interface{}) (*R
 
 	return &response, nil
 }
+
+// UpdateByQuery runs an _update_by_query request, the query holds both the query and the update script.
+func (i *index[T]) UpdateByQuery(ctx context.Context, query map[string]interface{}) error {
+	payload, _ := jsoniter.Marshal(query)
+
+	req := fasthttp.AcquireRequest()
+	req.SetBody(payload)
+	req.Header.SetMethod("POST")
+	req.Header.SetContentType("application/json")
+	req.SetRequestURI(i.connStr + "/" + i.name + "/_update_by_query?conflicts=proceed&refresh=true")
+	res := fasthttp.AcquireResponse()
+
+	deadline, _ := ctx.Deadline()
+
+	if err := fasthttp.DoDeadline(req, res, deadline); err != nil {
+		return err
+	}
+
+	fasthttp.ReleaseRequest(req)
+
+	if res.StatusCode() != fasthttp.StatusOK {
+		err := fmt.Errorf("elastic update by query on %s failed with status %d: %s", i.name, res.StatusCode(), res.Body())
+		fasthttp.ReleaseResponse(res)
+		return err
+	}
+
+	fasthttp.ReleaseResponse(res)
+
+	return nil
+}
+
+// Do sends a request with a json body to a path of the index, an empty path is the index itself.
+// It returns the response status code and body.
+func (i *index[T]) Do(ctx context.Context, method, path string, body []byte) (int, []byte, error) {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.SetBody(body)
+	req.Header.SetMethod(method)
+	req.Header.SetContentType("application/json")
+	req.SetRequestURI(i.connStr + "/" + i.name + path)
+
+	res := fasthttp.AcquireResponse()
+	defer fasthttp.ReleaseResponse(res)
+
+	deadline, _ := ctx.Deadline()
+
+	if err := fasthttp.DoDeadline(req, res, deadline); err != nil {
+		return 0, nil, err
+	}
+
+	return res.StatusCode(), append([]byte(nil), res.Body()...), nil
+}
@@ -6,16 +6,23 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/acikkaynak/backend-api-go/feeds"
 	"github.com/acikkaynak/backend-api-go/repository"
+	jsoniter "github.com/json-iterator/go"
 )
 
+// updateIDsBatchSize bounds the ids of one update by query request.
+const updateIDsBatchSize = 1000
+
 type LocationIndex struct {
 	connStr   string
 	index     *index[Location]
 	indexName string
+	// turkishSearch is set once the index has the full_text.tr subfield
+	turkishSearch atomic.Bool
 }
 
 func NewLocationIndex() *LocationIndex {
@@ -33,10 +40,261 @@ func NewLocationIndex() *LocationIndex {
 	}
 }
 
-func (l *LocationIndex) GetLocations(getLocationsQuery *repository.GetLocationsQuery) ([]feeds.Location, int, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*25)
+func (l *LocationIndex) GetLocations(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery) ([]feeds.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	filters := locationFilters(getLocationsQuery)
+
+	query := map[string]interface{}{
+		"track_total_hits": true,
+		"size":             feeds.MaxPageSize,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": filters,
+			},
+		},
+	}
+
+	sortByDistance := getLocationsQuery.SortByDistance && getLocationsQuery.HasRadius()
+
+	if sortByDistance {
+		// nearest first pages are not cursor paginated, limit only cuts the result
+		if getLocationsQuery.Limit > 0 {
+			query["size"] = getLocationsQuery.Limit
+		}
+		query["sort"] = []map[string]interface{}{
+			{"_geo_distance": map[string]interface{}{
+				"locations.center": map[string]interface{}{
+					"lat": getLocationsQuery.Lat,
+					"lon": getLocationsQuery.Lng,
+				},
+				"order": "asc",
+				"unit":  "m",
+			}},
+			{"id": map[string]interface{}{"order": "asc"}},
+		}
+	} else if getLocationsQuery.Limit > 0 {
+		// same ordering as the postgres read path, one extra hit tells whether there is a next page
+		query["size"] = getLocationsQuery.Limit + 1
+		query["sort"] = []map[string]interface{}{
+			{"epoch": map[string]interface{}{"order": "desc", "missing": 0}},
+			{"id": map[string]interface{}{"order": "desc"}},
+		}
+
+		if getLocationsQuery.Cursor != nil {
+			query["search_after"] = []int64{getLocationsQuery.Cursor.Epoch, getLocationsQuery.Cursor.ID}
+		}
+	}
+
+	res, err := l.index.Search(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var results []feeds.Location
+
+	for _, hit := range res.Hits.Hits {
+		location := toLocation(hit, getLocationsQuery)
+		if sortByDistance && len(hit.Sort) > 0 {
+			location.Distance = &hit.Sort[0]
+		}
+
+		results = append(results, location)
+	}
+
+	return results, nil
+}
+
+// toLocation converts a hit into the shape the postgres read path returns for the same query.
+func toLocation(hit Item[Location], getLocationsQuery *repository.GetLocationsQuery) feeds.Location {
+	source := hit.Source
+	id := source.ID
+	if id == 0 {
+		id, _ = strconv.ParseInt(hit.Id, 10, 64)
+	}
+	reasons := strings.Join(source.Reason, ",")
+	channels := strings.Join(source.Channel, ",")
+
+	var extraParameters *string
+	if getLocationsQuery.ExtraParams {
+		extraParameters = source.ExtraParameters
+		// a document merged from several feeds has their channels, any masked channel masks it
+		for _, channel := range source.Channel {
+			if masked := repository.MaskExtraParameters(channel, source.ExtraParameters); masked != source.ExtraParameters {
+				extraParameters = masked
+				break
+			}
+		}
+	}
+
+	var distance *float64
+	if getLocationsQuery.HasRadius() {
+		d := feeds.Distance(getLocationsQuery.Lat, getLocationsQuery.Lng,
+			source.RawLocations.Center.Lat, source.RawLocations.Center.Lon)
+		distance = &d
+	}
+
+	return feeds.Location{
+		ID:               id,
+		FormattedAddress: source.FormattedAddress,
+		Latitude:         source.RawLocations.Center.Lat,
+		Longitude:        source.RawLocations.Center.Lon,
+		Loc: []float64{
+			source.RawLocations.Center.Lat,
+			source.RawLocations.Center.Lon,
+		},
+		EntryID:            source.EntryId,
+		Epoch:              source.Epoch,
+		Reason:             &reasons,
+		Channel:            &channels,
+		IsLocationVerified: source.IsLocationVerified,
+		IsNeedVerified:     source.IsNeedVerified,
+		IsResolved:         source.IsResolved,
+		Needs:              source.Needs,
+		ReportCount:        source.ReportCount,
+		IsStale:            source.IsStale,
+		ExtraParameters:    extraParameters,
+		Distance:           distance,
+	}
+}
+
+// SearchLocations runs a full text query on the feed texts of the filtered locations, best matches first.
+func (l *LocationIndex) SearchLocations(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery, text string) ([]feeds.SearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
 	defer cancel()
 
+	size := getLocationsQuery.Limit
+	if size <= 0 {
+		size = feeds.DefaultSearchSize
+	}
+
+	searchFields := l.searchFields()
+	highlightFields := make(map[string]interface{}, len(searchFields))
+	for _, field := range searchFields {
+		highlightFields[field] = map[string]interface{}{
+			"fragment_size":       150,
+			"number_of_fragments": 3,
+		}
+	}
+
+	query := map[string]interface{}{
+		"size": size,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must": map[string]interface{}{
+					"multi_match": map[string]interface{}{
+						"query":    text,
+						"fields":   searchFields,
+						"type":     "most_fields",
+						"operator": "and",
+					},
+				},
+				"filter": locationFilters(getLocationsQuery),
+			},
+		},
+		"highlight": map[string]interface{}{
+			"pre_tags":  []string{feeds.HighlightPreTag},
+			"post_tags": []string{feeds.HighlightPostTag},
+			"fields":    highlightFields,
+		},
+	}
+
+	res, err := l.index.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []feeds.SearchResult
+
+	for _, hit := range res.Hits.Hits {
+		result := feeds.SearchResult{
+			Location: toLocation(hit, getLocationsQuery),
+		}
+
+		// the stemmed field matches more, fall back to the plain field when it has no fragments
+		for _, field := range searchFields {
+			if fragments := hit.Highlight[field]; len(fragments) > 0 {
+				result.Highlights = fragments
+				break
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// GetLocationClusters aggregates the filtered locations into geotile_grid cells of feeds.ClusterPrecision(zoom).
+func (l *LocationIndex) GetLocationClusters(ctx context.Context, getLocationsQuery *repository.GetLocationsQuery, zoom int) ([]feeds.Cluster, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*25)
+	defer cancel()
+
+	precision := feeds.ClusterPrecision(zoom)
+
+	query := map[string]interface{}{
+		"size": 0,
+		"query": map[string]interface{}{
+			"bool": map[string]interface{}{
+				"filter": locationFilters(getLocationsQuery),
+			},
+		},
+		"aggs": map[string]interface{}{
+			"clusters": map[string]interface{}{
+				"geotile_grid": map[string]interface{}{
+					"field":     "locations.center",
+					"precision": precision,
+					"size":      65535,
+				},
+				"aggs": map[string]interface{}{
+					"centroid": map[string]interface{}{
+						"geo_centroid": map[string]interface{}{
+							"field": "locations.center",
+						},
+					},
+					"reasons": map[string]interface{}{
+						"terms": map[string]interface{}{
+							"field": "reason",
+							"size":  100,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	res, err := l.index.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var agg GeoTileAggregation
+	if raw, ok := res.Aggregations["clusters"]; ok {
+		if err := jsoniter.Unmarshal(raw, &agg); err != nil {
+			return nil, err
+		}
+	}
+
+	builder := feeds.NewClusterBuilder()
+	for _, bucket := range agg.Buckets {
+		count := bucket.DocCount
+		builder.Add(bucket.Key, count,
+			bucket.Centroid.Location.Lat*float64(count),
+			bucket.Centroid.Location.Lon*float64(count),
+			nil)
+
+		for _, reason := range bucket.Reasons.Buckets {
+			builder.AddReason(bucket.Key, reason.Key, reason.DocCount)
+		}
+	}
+
+	return builder.Clusters(), nil
+}
+
+// locationFilters converts the GetLocationsQuery filters into elastic bool filters.
+func locationFilters(getLocationsQuery *repository.GetLocationsQuery) []map[string]interface{} {
 	var filters []map[string]interface{}
 
 	if getLocationsQuery.SwLat != 0.0 || getLocationsQuery.SwLng != 0.0 || getLocationsQuery.NeLat != 0.0 || getLocationsQuery.NeLng != 0.0 {
@@ -117,48 +375,97 @@ func (l *LocationIndex) GetLocations(getLocationsQuery *repository.GetLocationsQ
 		})
 	}
 
-	query := map[string]interface{}{
-		"track_total_hits": true,
-		"size":             10000,
-		"query": map[string]interface{}{
+	if isResolved, err := strconv.ParseBool(getLocationsQuery.IsResolved); err == nil {
+		if isResolved {
+			filters = append(filters, map[string]interface{}{
+				"term": map[string]interface{}{
+					"is_resolved": true,
+				},
+			})
+		} else {
+			// documents indexed before resolution was tracked have no is_resolved field
+			filters = append(filters, map[string]interface{}{
+				"bool": map[string]interface{}{
+					"must_not": map[string]interface{}{
+						"term": map[string]interface{}{
+							"is_resolved": true,
+						},
+					},
+				},
+			})
+		}
+	}
+
+	if getLocationsQuery.ExcludeSatisfied {
+		// locations without needs or with at least one open need
+		filters = append(filters, map[string]interface{}{
 			"bool": map[string]interface{}{
-				"filter": filters,
+				"should": []map[string]interface{}{
+					{"bool": map[string]interface{}{
+						"must_not": map[string]interface{}{
+							"exists": map[string]interface{}{"field": "needs.label"},
+						},
+					}},
+					{"term": map[string]interface{}{"needs.status": true}},
+				},
+				"minimum_should_match": 1,
 			},
-		},
+		})
 	}
 
-	res, err := l.index.Search(ctx, query)
-
-	if err != nil {
-		return nil, 0, err
+	switch getLocationsQuery.Stale {
+	case feeds.StaleExclude:
+		// documents indexed before staleness was tracked have no is_stale field
+		filters = append(filters, map[string]interface{}{
+			"bool": map[string]interface{}{
+				"must_not": map[string]interface{}{
+					"term": map[string]interface{}{
+						"is_stale": true,
+					},
+				},
+			},
+		})
+	case feeds.StaleOnly:
+		filters = append(filters, map[string]interface{}{
+			"term": map[string]interface{}{
+				"is_stale": true,
+			},
+		})
 	}
 
-	var results []feeds.Location
+	filters = append(filters, map[string]interface{}{
+		"term": map[string]interface{}{
+			"is_deleted": false,
+		},
+	})
 
-	for _, hit := range res.Hits.Hits {
-		source := hit.Source
-		id, _ := strconv.ParseInt(hit.Id, 10, 64)
-		reasons := strings.Join(source.Reason, ",")
-		channels := strings.Join(source.Channel, ",")
-
-		results = append(results, feeds.Location{
-			ID: id,
-			Loc: []float64{
-				source.RawLocations.Center.Lat,
-				source.RawLocations.Center.Lon,
-			},
-			EntryID:            source.EntryId,
-			Epoch:              source.Epoch,
-			Reason:             &reasons,
-			Channel:            &channels,
-			IsLocationVerified: source.IsLocationVerified,
-			IsNeedVerified:     source.IsNeedVerified,
-			Needs:              source.Needs,
-			ExtraParameters:    source.ExtraParameters,
+	if getLocationsQuery.HasRadius() {
+		filters = append(filters, map[string]interface{}{
+			"geo_distance": map[string]interface{}{
+				"distance": strconv.FormatFloat(getLocationsQuery.RadiusM, 'f', -1, 64) + "m",
+				"locations.center": map[string]interface{}{
+					"lat": getLocationsQuery.Lat,
+					"lon": getLocationsQuery.Lng,
+				},
+			},
+		})
+	}
+
+	if len(getLocationsQuery.Polygon) > 0 {
+		filters = append(filters, map[string]interface{}{
+			"geo_shape": map[string]interface{}{
+				"locations.center": map[string]interface{}{
+					"shape": map[string]interface{}{
+						"type":        "polygon",
+						"coordinates": [][][]float64{getLocationsQuery.Polygon},
+					},
+					"relation": "intersects",
+				},
+			},
 		})
 	}
 
-	return results, res.Hits.Total.Value, nil
+	return filters
 }
 
 func (l *LocationIndex) CreateFeedLocation(ctx context.Context, fullText string, location feeds.Location) error {
@@ -191,6 +498,7 @@ func (l *LocationIndex) CreateFeedLocation(ctx context.Context, fullText string,
 		Index: l.indexName,
 		Id:    strconv.FormatInt(location.ID, 10),
 		Source: Location{
+			ID:                 location.ID,
 			FormattedAddress:   location.FormattedAddress,
 			Locations:          locations,
 			RawLocations:       locations,
@@ -200,12 +508,124 @@ func (l *LocationIndex) CreateFeedLocation(ctx context.Context, fullText string,
 			Reason:             reason,
 			EntryId:            location.EntryID,
 			Epoch:              location.Epoch,
-			IsLocationVerified: false,
-			IsNeedVerified:     false,
+			IsLocationVerified: location.IsLocationVerified,
+			IsNeedVerified:     location.IsNeedVerified,
 			IsDeleted:          false,
+			IsResolved:         location.IsResolved,
 			Needs:              location.Needs,
+			ReportCount:        location.ReportCount,
+			IsStale:            location.IsStale,
 		},
 	}
 
 	return l.index.Bulk(ctx, []Item[Location]{item})
 }
+
+// SetResolved updates is_resolved on every location document of a feed entry.
+func (l *LocationIndex) SetResolved(ctx context.Context, entryID int64, isResolved bool) error {
+	return l.updateEntryLocations(ctx, entryID, "ctx._source.is_resolved = params.is_resolved", map[string]interface{}{
+		"is_resolved": isResolved,
+	})
+}
+
+// SetNeedStatus updates the status of a need label on every location document of a feed entry.
+func (l *LocationIndex) SetNeedStatus(ctx context.Context, entryID int64, label string, status bool) error {
+	script := "if (ctx._source.needs != null) { for (need in ctx._source.needs) { " +
+		"if (need.label == params.label) { need.status = params.status } } }"
+
+	return l.updateEntryLocations(ctx, entryID, script, map[string]interface{}{
+		"label":  label,
+		"status": status,
+	})
+}
+
+// SetNeedVerification stores the reviewed intents and needs of a location and marks its needs verified.
+func (l *LocationIndex) SetNeedVerification(ctx context.Context, locationID int64, reason *string, needs []feeds.NeedItem) error {
+	var reasons []string
+	if reason != nil && *reason != "" {
+		reasons = strings.Split(*reason, ",")
+	}
+
+	script := "ctx._source.reason = params.reason; ctx._source.needs = params.needs; ctx._source.is_need_verified = true"
+
+	return l.updateLocations(ctx, map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": []string{strconv.FormatInt(locationID, 10)},
+		},
+	}, script, map[string]interface{}{
+		"reason": reasons,
+		"needs":  needs,
+	})
+}
+
+// SetReportCount updates the number of duplicate reports counted on the canonical location of a cluster,
+// the report also makes a stale location current again.
+func (l *LocationIndex) SetReportCount(ctx context.Context, locationID int64, reportCount int) error {
+	return l.updateLocations(ctx, map[string]interface{}{
+		"ids": map[string]interface{}{
+			"values": []string{strconv.FormatInt(locationID, 10)},
+		},
+	}, "ctx._source.report_count = params.report_count; ctx._source.is_stale = false", map[string]interface{}{
+		"report_count": reportCount,
+	})
+}
+
+// SetStale flags the location documents with the given ids as stale.
+func (l *LocationIndex) SetStale(ctx context.Context, locationIDs []int64) error {
+	return l.updateLocationIDs(ctx, locationIDs, "ctx._source.is_stale = true")
+}
+
+// SetCurrent clears the stale flag of the location documents with the given ids.
+func (l *LocationIndex) SetCurrent(ctx context.Context, locationIDs []int64) error {
+	return l.updateLocationIDs(ctx, locationIDs, "ctx._source.is_stale = false")
+}
+
+// updateLocationIDs runs a painless script on the location documents with the given ids,
+// updateIDsBatchSize documents per request.
+func (l *LocationIndex) updateLocationIDs(ctx context.Context, locationIDs []int64, script string) error {
+	for start := 0; start < len(locationIDs); start += updateIDsBatchSize {
+		end := start + updateIDsBatchSize
+		if end > len(locationIDs) {
+			end = len(locationIDs)
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, id := range locationIDs[start:end] {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+
+		if err := l.updateLocations(ctx, map[string]interface{}{
+			"ids": map[string]interface{}{
+				"values": ids,
+			},
+		}, script, map[string]interface{}{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// updateEntryLocations runs a painless script on every location document of a feed entry.
+func (l *LocationIndex) updateEntryLocations(ctx context.Context, entryID int64, script string, params map[string]interface{}) error {
+	return l.updateLocations(ctx, map[string]interface{}{
+		"term": map[string]interface{}{
+			"entry_id": entryID,
+		},
+	}, script, params)
+}
+
+// updateLocations runs a painless script on the location documents matching query.
+func (l *LocationIndex) updateLocations(ctx context.Context, query map[string]interface{}, script string, params map[string]interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*10)
+	defer cancel()
+
+	return l.index.UpdateByQuery(ctx, map[string]interface{}{
+		"query": query,
+		"script": map[string]interface{}{
+			"source": script,
+			"lang":   "painless",
+			"params": params,
+		},
+	})
+}
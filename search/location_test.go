@@ -0,0 +1,25 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/acikkaynak/backend-api-go/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToLocationMasksAnyMaskedChannel(t *testing.T) {
+	extraParameters := `{"tel": "05555555555", "name": "Ali Veli"}`
+	hit := Item[Location]{Id: "1", Source: Location{
+		Channel:         []string{"web", "twitter"},
+		ExtraParameters: &extraParameters,
+	}}
+
+	location := toLocation(hit, &repository.GetLocationsQuery{ExtraParams: true})
+	assert.NotNil(t, location.ExtraParameters)
+	assert.NotContains(t, *location.ExtraParameters, "Ali Veli")
+	assert.Equal(t, int64(1), location.ID)
+
+	hit.Source.Channel = []string{"web"}
+	location = toLocation(hit, &repository.GetLocationsQuery{ExtraParams: true})
+	assert.Equal(t, &extraParameters, location.ExtraParameters)
+}
@@ -0,0 +1,139 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	log "github.com/acikkaynak/backend-api-go/pkg/logger"
+	"github.com/acikkaynak/backend-api-go/resources"
+	jsoniter "github.com/json-iterator/go"
+	"go.uber.org/zap"
+)
+
+const (
+	turkishSearchField = "full_text.tr"
+	mappingRetryDelay  = time.Minute
+)
+
+// indexDefinition is the part of resources.LocationsIndex needed to add the turkish subfield.
+type indexDefinition struct {
+	Settings struct {
+		Analysis jsoniter.RawMessage `json:"analysis"`
+	} `json:"settings"`
+	Mappings struct {
+		Properties struct {
+			FullText jsoniter.RawMessage `json:"full_text"`
+		} `json:"properties"`
+	} `json:"mappings"`
+}
+
+// RunTurkishSearchSetup applies the turkish full_text.tr subfield to the locations index, retrying
+// until it succeeds or ctx is done. Searches use the plain full_text field until then.
+func (l *LocationIndex) RunTurkishSearchSetup(ctx context.Context) {
+	for {
+		err := l.EnsureTurkishSearch(ctx)
+		if err == nil {
+			return
+		}
+		log.Logger().Error("could not set up turkish search on elastic", zap.Error(err))
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(mappingRetryDelay):
+		}
+	}
+}
+
+// EnsureTurkishSearch brings the locations index in line with resources/locations_index.json. A missing
+// index is created from the file. An existing index without the full_text.tr subfield is closed for a moment
+// to add the turkish analyzer, gets the subfield and has its documents reindexed in place in the background,
+// documents not reindexed yet are still found through full_text.
+func (l *LocationIndex) EnsureTurkishSearch(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Second*30)
+	defer cancel()
+
+	status, body, err := l.index.Do(ctx, http.MethodGet, "/_mapping/field/"+turkishSearchField, nil)
+	if err != nil {
+		return err
+	}
+
+	switch status {
+	case http.StatusNotFound:
+		if err := l.send(ctx, http.MethodPut, "", resources.LocationsIndex); err != nil {
+			return err
+		}
+		l.turkishSearch.Store(true)
+		return nil
+	case http.StatusOK:
+	default:
+		return fmt.Errorf("elastic mapping of %s failed with status %d: %s", l.indexName, status, body)
+	}
+
+	var mappings map[string]struct {
+		Mappings map[string]interface{} `json:"mappings"`
+	}
+	if err := jsoniter.Unmarshal(body, &mappings); err != nil {
+		return err
+	}
+	for _, index := range mappings {
+		if _, ok := index.Mappings[turkishSearchField]; ok {
+			l.turkishSearch.Store(true)
+			return nil
+		}
+	}
+
+	var definition indexDefinition
+	if err := jsoniter.Unmarshal(resources.LocationsIndex, &definition); err != nil {
+		return err
+	}
+
+	analysis, _ := jsoniter.Marshal(map[string]interface{}{"analysis": definition.Settings.Analysis})
+	fullText, _ := jsoniter.Marshal(map[string]interface{}{
+		"properties": map[string]interface{}{"full_text": definition.Mappings.Properties.FullText},
+	})
+
+	// analyzers can only be added to a closed index
+	if err := l.send(ctx, http.MethodPost, "/_close", nil); err != nil {
+		return err
+	}
+	err = l.send(ctx, http.MethodPut, "/_settings", analysis)
+	if openErr := l.send(ctx, http.MethodPost, "/_open", nil); err == nil {
+		err = openErr
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := l.send(ctx, http.MethodPut, "/_mapping", fullText); err != nil {
+		return err
+	}
+	l.turkishSearch.Store(true)
+
+	log.Logger().Info("added turkish search to elastic, reindexing locations", zap.String("index", l.indexName))
+
+	return l.send(ctx, http.MethodPost, "/_update_by_query?conflicts=proceed&wait_for_completion=false", nil)
+}
+
+// searchFields are the analyzed full_text fields, full_text.tr uses the turkish analyzer of
+// resources/locations_index.json and is left out until the index has it.
+func (l *LocationIndex) searchFields() []string {
+	if l.turkishSearch.Load() {
+		return []string{turkishSearchField, "full_text"}
+	}
+	return []string{"full_text"}
+}
+
+// send sends a request to the index and fails on any status but 200.
+func (l *LocationIndex) send(ctx context.Context, method, path string, body []byte) error {
+	status, respBody, err := l.index.Do(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("elastic %s %s%s failed with status %d: %s", method, l.indexName, path, status, respBody)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package search
 
 import (
 	"github.com/acikkaynak/backend-api-go/feeds"
+	jsoniter "github.com/json-iterator/go"
 )
 
 // Common Models
@@ -11,7 +12,8 @@ type Total struct {
 }
 
 type Result[T any] struct {
-	Hits Hits[T] `json:"hits"`
+	Hits         Hits[T]                        `json:"hits"`
+	Aggregations map[string]jsoniter.RawMessage `json:"aggregations,omitempty"`
 }
 
 type Hits[T any] struct {
@@ -20,9 +22,11 @@ type Hits[T any] struct {
 }
 
 type Item[T any] struct {
-	Index  string `json:"_index"`
-	Id     string `json:"_id"`
-	Source T      `json:"_source"`
+	Index     string              `json:"_index"`
+	Id        string              `json:"_id"`
+	Source    T                   `json:"_source"`
+	Sort      []float64           `json:"sort,omitempty"`
+	Highlight map[string][]string `json:"highlight,omitempty"`
 }
 
 // Location Index Specific Models
@@ -39,6 +43,7 @@ type Locations struct {
 }
 
 type Location struct {
+	ID                 int64            `json:"id"`
 	FormattedAddress   string           `json:"formatted_address"`
 	Locations          Locations        `json:"locations"`
 	RawLocations       Locations        `json:"raw_locations"`
@@ -51,5 +56,28 @@ type Location struct {
 	IsLocationVerified bool             `json:"is_location_verified"`
 	IsNeedVerified     bool             `json:"is_need_verified"`
 	IsDeleted          bool             `json:"is_deleted"`
+	IsResolved         bool             `json:"is_resolved"`
 	Needs              []feeds.NeedItem `json:"needs,omitempty"`
+	ReportCount        int              `json:"report_count,omitempty"`
+	IsStale            bool             `json:"is_stale"`
+}
+
+type GeoTileAggregation struct {
+	Buckets []GeoTileBucket `json:"buckets"`
+}
+
+type GeoTileBucket struct {
+	Key      string `json:"key"`
+	DocCount int    `json:"doc_count"`
+	Centroid struct {
+		Location Coordinates `json:"location"`
+	} `json:"centroid"`
+	Reasons struct {
+		Buckets []TermBucket `json:"buckets"`
+	} `json:"reasons"`
+}
+
+type TermBucket struct {
+	Key      string `json:"key"`
+	DocCount int    `json:"doc_count"`
 }
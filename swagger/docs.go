@@ -19,6 +19,90 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/admin/feeds/deleted": {
+            "get": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "List feeds deleted automatically as irrelevant or duplicate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "irrelevant or duplicate",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1000 by default, max 10000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.DeletedLocationsResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/feeds/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds 503 when the feed could not be indexed, restoring it again retries the indexing.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Admin"
+                ],
+                "summary": "Restore an automatically deleted feed and index it again",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who restored the feed",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.Response"
+                        }
+                    }
+                }
+            }
+        },
         "/events": {
             "post": {
                 "security": [
@@ -56,8 +140,11 @@ const docTemplate = `{
         },
         "/feeds/areas": {
             "get": {
+                "description": "Duplicate reports of a place are returned as one location, report_count tells how many reports it stands for.\nA paged ndjson stream which has a next page ends with a {\"next_cursor\": \"...\"} line.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/geo+json",
+                    "application/x-ndjson"
                 ],
                 "tags": [
                     "Feed"
@@ -109,16 +196,85 @@ const docTemplate = `{
                         "description": "Channel",
                         "name": "channel",
                         "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only resolved or only unresolved feeds",
+                        "name": "is_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave out locations whose needs have all been delivered",
+                        "name": "exclude_satisfied",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "include (default), exclude or only locations which expired under the staleness policy",
+                        "name": "stale",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, max 10000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, geojson for a FeatureCollection, ndjson to stream one location per line",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter center latitude",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter center longitude",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter in meters, max 100000",
+                        "name": "radius_m",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "GeoJSON Polygon geometry",
+                        "name": "polygon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "distance sorts radius results nearest first",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "postgres or elastic, elastic falls back to postgres on errors",
+                        "name": "backend",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/feeds.Result"
-                            }
+                            "$ref": "#/definitions/feeds.Response"
                         }
                     }
                 }
@@ -148,6 +304,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/feeds.UpdateFeedLocationsRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who corrected the locations",
+                        "name": "X-Actor",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -157,191 +319,2478 @@ const docTemplate = `{
                 }
             }
         },
-        "/feeds/{id}": {
+        "/feeds/changes": {
             "get": {
+                "description": "Returns upserted locations and ids of deleted ones. Start without a token, then pass next_token of the previous response; keep asking while has_more is true.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Feed"
                 ],
-                "summary": "Get Feeds with given id",
+                "summary": "Get feed locations changed since a change token",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Change token",
+                        "name": "since",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
-                        "description": "Feed Id",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/feeds.Feed"
-                        }
+                        "description": "Max changes, max 10000",
+                        "name": "limit",
+                        "in": "query"
                     }
-                }
-            }
-        },
-        "/healthcheck": {
-            "get": {
-                "description": "get the status of server.",
-                "consumes": [
-                    "*/*"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "HealthCheck"
                 ],
-                "summary": "Show the status of server.",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/feeds.ChangesResponse"
                         }
                     }
                 }
             }
         },
-        "/needs": {
+        "/feeds/clusters": {
             "get": {
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Need"
+                    "Feed"
                 ],
-                "summary": "Get Needs",
+                "summary": "Get clustered feed locations for a map zoom level",
                 "parameters": [
                     {
-                        "type": "boolean",
-                        "description": "Is Only Not Resolved",
-                        "name": "only_not_resolved",
+                        "type": "integer",
+                        "description": "Map zoom level, 0-20",
+                        "name": "zoom",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lat",
+                        "name": "sw_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lng",
+                        "name": "sw_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lat",
+                        "name": "ne_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lng",
+                        "name": "ne_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Timestamp",
+                        "name": "time_stamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "postgres or elastic, elastic falls back to postgres on errors",
+                        "name": "backend",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/needs.Response"
+                            "$ref": "#/definitions/feeds.ClusterResponse"
                         }
                     }
                 }
-            },
-            "post": {
+            }
+        },
+        "/feeds/export": {
+            "get": {
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
                 ],
+                "description": "Takes the filters of /feeds/areas and streams every matching location, contact fields are masked. Every export is recorded in the export log.",
                 "produces": [
-                    "application/json"
+                    "text/csv",
+                    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
                 ],
                 "tags": [
-                    "Need"
+                    "Feed"
                 ],
-                "summary": "Create Need",
+                "summary": "Export feed locations as a spreadsheet",
                 "parameters": [
                     {
-                        "description": "RequestBody",
-                        "name": "body",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/needs.CreateNeedRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/needs.LiteNeed"
-                        }
-                    }
-                }
-            }
-        }
-    },
-    "definitions": {
-        "feeds.Feed": {
-            "type": "object",
-            "properties": {
-                "channel": {
-                    "type": "string"
-                },
-                "epoch": {
-                    "type": "integer"
-                },
-                "extra_parameters": {
-                    "type": "string"
-                },
-                "formatted_address": {
-                    "type": "string"
-                },
-                "full_text": {
-                    "type": "string"
+                        "type": "string",
+                        "description": "csv (default) or xlsx",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lat",
+                        "name": "sw_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lng",
+                        "name": "sw_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lat",
+                        "name": "ne_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lng",
+                        "name": "ne_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Timestamp",
+                        "name": "time_stamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only resolved or only unresolved feeds",
+                        "name": "is_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "include (default), exclude or only stale locations",
+                        "name": "stale",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    }
+                }
+            }
+        },
+        "/feeds/heatmap": {
+            "get": {
+                "produces": [
+                    "application/json",
+                    "image/png"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Get need density over a bounding box",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Sw Lat",
+                        "name": "sw_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lng",
+                        "name": "sw_lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lat",
+                        "name": "ne_lat",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lng",
+                        "name": "ne_lng",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Grid columns, max 512",
+                        "name": "cols",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Grid rows, max 512",
+                        "name": "rows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Hours after which a location weighs half, 72 by default",
+                        "name": "half_life_h",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason weights like enkaz:5,giyim:0.5",
+                        "name": "weights",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Timestamp",
+                        "name": "time_stamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "png for a raster, one pixel per cell",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/heatmap.Grid"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/search": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Full text search over feed texts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text, quoted phrases and -word are supported",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lat",
+                        "name": "sw_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Sw Lng",
+                        "name": "sw_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lat",
+                        "name": "ne_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Ne Lng",
+                        "name": "ne_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Timestamp",
+                        "name": "time_stamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter center latitude",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter center longitude",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius filter in meters, max 100000",
+                        "name": "radius_m",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "GeoJSON Polygon geometry",
+                        "name": "polygon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Result size, 100 by default, max 1000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "postgres or elastic, elastic by default and falls back to postgres on errors",
+                        "name": "backend",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.SearchResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}": {
+            "get": {
+                "description": "Feeds in a duplicate cluster list every report of the cluster in cluster_members.\nThe id of any location of an entry returns the entry, described by that location.",
+                "produces": [
+                    "application/json",
+                    "application/geo+json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Get Feeds with given id",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id or location id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Response format, geojson for a Feature",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.Feed"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Get the location corrections of a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.LocationHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/history/{correctionId}/rollback": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Restore a feed location to what it was before a correction",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Id of the correction to roll back",
+                        "name": "correctionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who rolled the location back",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.LocationCorrection"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/needs/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Get the need status changes of a feed",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.NeedHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/needs/{label}": {
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Responds 503 when the status could not reach search, retrying the same request completes it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Mark a need of a feed as open or delivered",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Need label",
+                        "name": "label",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "false once the need is delivered",
+                        "name": "UpdateNeedStatusRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/feeds.UpdateNeedStatusRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who updated the need",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.NeedHistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/reopen": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Mark a resolved feed as not handled",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.Feed"
+                        }
+                    }
+                }
+            }
+        },
+        "/feeds/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Feed"
+                ],
+                "summary": "Mark a feed as handled",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Feed Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Outcome of the request",
+                        "name": "ResolveFeedRequest",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.ResolveFeedRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who resolved the feed",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.Feed"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthcheck": {
+            "get": {
+                "description": "get the status of server.",
+                "consumes": [
+                    "*/*"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HealthCheck"
+                ],
+                "summary": "Show the status of server.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs": {
+            "get": {
+                "description": "Lists needs newest first. With limit or cursor a page holds limit needs and next_cursor points at the next one.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Get Needs",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Is Only Not Resolved",
+                        "name": "only_not_resolved",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Southwest latitude of the bounding box",
+                        "name": "sw_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Southwest longitude of the bounding box",
+                        "name": "sw_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Northeast latitude of the bounding box",
+                        "name": "ne_lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Northeast longitude of the bounding box",
+                        "name": "ne_lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Latitude of the radius filter center",
+                        "name": "lat",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Longitude of the radius filter center",
+                        "name": "lng",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Radius in meters, up to 100000",
+                        "name": "radius_m",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Needs created at or after this unix timestamp",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Needs created before this unix timestamp",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Text searched in the description",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated categories out of /reasons, needs asking for any of them",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "newest, oldest or distance (needs radius_m)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, max 10000, 1000 when only a cursor is given",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Categories have to be out of /reasons, a quantity and unit like 50 battaniye are optional.\nA need likely repeating a recent one at the same address is not created, the duplicates are returned\nwith 409 and sending the request again with confirm=true creates it anyway.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Create Need",
+                "parameters": [
+                    {
+                        "description": "RequestBody",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/needs.CreateNeedRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who created the need",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.LiteNeed"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/needs.DuplicatesResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Get Need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.Need"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Delete Need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who deleted the need",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates the description, address or extra parameters of a need, a new address is geocoded again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Edit Need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/needs.UpdateNeedRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who changed the need",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.Need"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs/{id}/history": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Get the recorded changes of a need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.HistoryResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs/{id}/matches": {
+            "get": {
+                "description": "Lists the nearest active offers of the need's categories, the need has to be geocoded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Propose supply offers for a need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of matches, 20 by default, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/matching.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs/{id}/merge": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "The duplicates are deleted, their categories are combined into the need and its history includes theirs.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Merge duplicate needs into a need",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Needs to merge",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/needs.MergeNeedsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who merged the needs",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.Need"
+                        }
+                    }
+                }
+            }
+        },
+        "/needs/{id}/resolve": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Need"
+                ],
+                "summary": "Mark a need as resolved",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Need Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who resolved the need",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/needs.Need"
+                        }
+                    }
+                }
+            }
+        },
+        "/offers": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Offer"
+                ],
+                "summary": "List supply offers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated categories out of /reasons, offers of any of them",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Leave out offers which are not available anymore",
+                        "name": "only_active",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, 1000 by default, max 10000",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor returned as next_cursor by the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/offers.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Categories have to be out of /reasons, a quantity and unit like 2000 battaniye are optional.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Offer"
+                ],
+                "summary": "Register a supply offer",
+                "parameters": [
+                    {
+                        "description": "RequestBody",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/offers.CreateOfferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who registered the offer",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/offers.Offer"
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Offer"
+                ],
+                "summary": "Get a supply offer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/offers.Offer"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Fields left out are kept, is_active=false marks an offer as no longer available.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Offer"
+                ],
+                "summary": "Edit a supply offer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/offers.UpdateOfferRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Who changed the offer",
+                        "name": "X-Actor",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/offers.Offer"
+                        }
+                    }
+                }
+            }
+        },
+        "/offers/{id}/matches": {
+            "get": {
+                "description": "Lists the nearest open needs and feed locations asking for the offer's categories.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Offer"
+                ],
+                "summary": "Propose needs and feed locations for a supply offer",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Offer Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of matches, 20 by default, max 100",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/matching.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/claim": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Claim locations whose needs are not verified yet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Locations to claim, 10 by default, max 50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewer",
+                        "name": "X-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.ReviewQueueResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Verify the intents and needs of a claimed location",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Location Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Corrected intents and needs, empty to confirm them",
+                        "name": "SubmitReviewRequest",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.SubmitReviewRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewer",
+                        "name": "X-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/feeds.ReviewResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/reviews/{id}/claim": {
+            "delete": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "tags": [
+                    "Review"
+                ],
+                "summary": "Give a claimed location back to the review queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Location Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reviewer",
+                        "name": "X-Actor",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    }
+                }
+            }
+        },
+        "/tiles/{z}/{x}/{y}.mvt": {
+            "get": {
+                "description": "A tile holds the newest 20000 locations, /feeds/clusters covers every location at low zoom levels.",
+                "produces": [
+                    "application/vnd.mapbox-vector-tile"
+                ],
+                "tags": [
+                    "Tile"
+                ],
+                "summary": "Get feed locations as a Mapbox Vector Tile",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Zoom",
+                        "name": "z",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tile column",
+                        "name": "x",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Tile row",
+                        "name": "y",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Timestamp",
+                        "name": "time_stamp",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason",
+                        "name": "reason",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel",
+                        "name": "channel",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "feeds.ChangesResponse": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "type": "boolean"
+                },
+                "next_token": {
+                    "type": "string"
+                },
+                "tombstones": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "upserts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.Location"
+                    }
+                }
+            }
+        },
+        "feeds.Cluster": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "reasons": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "feeds.ClusterMember": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "full_text": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "feeds.ClusterResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.Cluster"
+                    }
+                }
+            }
+        },
+        "feeds.DeletedLocation": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "deleted_at": {
+                    "type": "string"
+                },
+                "deleted_reason": {
+                    "type": "string"
+                },
+                "deleted_scores": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number"
+                    }
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "full_text": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_location_verified": {
+                    "type": "boolean"
+                },
+                "is_need_verified": {
+                    "type": "boolean"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "is_stale": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "northeast_lat": {
+                    "type": "number"
+                },
+                "northeast_lng": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "southwest_lat": {
+                    "type": "number"
+                },
+                "southwest_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.DeletedLocationsResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.DeletedLocation"
+                    }
+                }
+            }
+        },
+        "feeds.Feed": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "cluster_id": {
+                    "description": "ClusterID is the id of the canonical location when other reports were found to be duplicates\nof the same place, ClusterMembers lists every report of the cluster, canonical report first",
+                    "type": "integer"
+                },
+                "cluster_members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.ClusterMember"
+                    }
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "full_text": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lng": {
+                    "type": "number"
+                },
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.Location"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
+                    "type": "string"
+                },
+                "resolved_outcome": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                }
+            }
+        },
+        "feeds.FeedLocation": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.Location": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_location_verified": {
+                    "type": "boolean"
+                },
+                "is_need_verified": {
+                    "type": "boolean"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "is_stale": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "northeast_lat": {
+                    "type": "number"
+                },
+                "northeast_lng": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "southwest_lat": {
+                    "type": "number"
+                },
+                "southwest_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.LocationCorrection": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "current": {
+                    "$ref": "#/definitions/feeds.LocationSnapshot"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "$ref": "#/definitions/feeds.LocationSnapshot"
+                },
+                "rolled_back_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "feeds.LocationHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.LocationCorrection"
+                    }
+                }
+            }
+        },
+        "feeds.LocationSnapshot": {
+            "type": "object",
+            "properties": {
+                "formatted_address": {
+                    "type": "string"
+                },
+                "is_location_verified": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.NeedHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedStatusChange"
+                    }
+                }
+            }
+        },
+        "feeds.NeedItem": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "feeds.NeedStatusChange": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "previous_status": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "feeds.ResolveFeedRequest": {
+            "type": "object",
+            "properties": {
+                "outcome": {
+                    "type": "string"
+                }
+            }
+        },
+        "feeds.Response": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.Location"
+                    }
+                }
+            }
+        },
+        "feeds.ReviewItem": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "claimed_by": {
+                    "type": "string"
+                },
+                "claimed_until": {
+                    "type": "string"
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "full_text": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_location_verified": {
+                    "type": "boolean"
+                },
+                "is_need_verified": {
+                    "type": "boolean"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "is_stale": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "northeast_lat": {
+                    "type": "number"
+                },
+                "northeast_lng": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "southwest_lat": {
+                    "type": "number"
+                },
+                "southwest_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.ReviewQueueResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.ReviewItem"
+                    }
+                }
+            }
+        },
+        "feeds.ReviewResult": {
+            "type": "object",
+            "properties": {
+                "entry_id": {
+                    "type": "integer"
+                },
+                "is_need_verified": {
+                    "type": "boolean"
+                },
+                "location_id": {
+                    "type": "integer"
+                },
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "verified_at": {
+                    "type": "string"
+                },
+                "verified_by": {
+                    "type": "string"
+                }
+            }
+        },
+        "feeds.SearchResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.SearchResult"
+                    }
+                }
+            }
+        },
+        "feeds.SearchResult": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "entry_id": {
+                    "type": "integer"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "highlights": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_location_verified": {
+                    "type": "boolean"
+                },
+                "is_need_verified": {
+                    "type": "boolean"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "is_stale": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "longitude": {
+                    "type": "number"
+                },
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "northeast_lat": {
+                    "type": "number"
+                },
+                "northeast_lng": {
+                    "type": "number"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "report_count": {
+                    "type": "integer"
+                },
+                "southwest_lat": {
+                    "type": "number"
+                },
+                "southwest_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "feeds.SubmitReviewRequest": {
+            "type": "object",
+            "properties": {
+                "needs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.NeedItem"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "feeds.UpdateFeedLocationsRequest": {
+            "type": "object",
+            "properties": {
+                "feed_locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/feeds.FeedLocation"
+                    }
+                }
+            }
+        },
+        "feeds.UpdateNeedStatusRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.RawFeed": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "epoch": {
+                    "type": "integer"
+                },
+                "extra_parameters": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "raw_text": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.request": {
+            "type": "object",
+            "properties": {
+                "feeds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.RawFeed"
+                    }
+                }
+            }
+        },
+        "heatmap.Grid": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "description": "Cells holds Rows rows of Cols values, north to south and west to east",
+                    "type": "array",
+                    "items": {
+                        "type": "array",
+                        "items": {
+                            "type": "number"
+                        }
+                    }
+                },
+                "cols": {
+                    "type": "integer"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "ne_lat": {
+                    "type": "number"
+                },
+                "ne_lng": {
+                    "type": "number"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "sw_lat": {
+                    "type": "number"
+                },
+                "sw_lng": {
+                    "type": "number"
+                }
+            }
+        },
+        "matching.Match": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "distance_m": {
+                    "type": "number"
+                },
+                "entry_id": {
+                    "type": "integer"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_resolved": {
-                    "type": "boolean"
+                "kind": {
+                    "type": "string"
                 },
-                "reason": {
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                }
+            }
+        },
+        "matching.Response": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/matching.Match"
+                    }
+                }
+            }
+        },
+        "needs.Category": {
+            "type": "object",
+            "properties": {
+                "category": {
                     "type": "string"
                 },
-                "timestamp": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit": {
                     "type": "string"
                 }
             }
         },
-        "feeds.FeedLocation": {
+        "needs.Change": {
             "type": "object",
             "properties": {
-                "address": {
+                "action": {
                     "type": "string"
                 },
-                "entry_id": {
+                "changed_at": {
+                    "type": "string"
+                },
+                "changed_by": {
+                    "type": "string"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
                     "type": "integer"
                 },
-                "latitude": {
-                    "type": "number"
+                "need_id": {
+                    "type": "integer"
                 },
-                "longitude": {
-                    "type": "number"
+                "previous": {
+                    "type": "object",
+                    "additionalProperties": true
                 }
             }
         },
-        "feeds.Result": {
+        "needs.CreateNeedRequest": {
             "type": "object",
+            "required": [
+                "address",
+                "description"
+            ],
             "properties": {
-                "channel": {
+                "address": {
                     "type": "string"
                 },
-                "entry_id": {
-                    "type": "integer"
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
                 },
-                "epoch": {
-                    "type": "integer"
+                "confirm": {
+                    "description": "Confirm creates the need even if it likely repeats an existing one",
+                    "type": "boolean"
+                },
+                "description": {
+                    "type": "string"
+                }
+            }
+        },
+        "needs.Duplicate": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
                 },
                 "extra_parameters": {
                     "type": "string"
                 },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "geocode_status": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "is_location_verified": {
-                    "type": "boolean"
-                },
-                "is_need_verified": {
+                "is_resolved": {
                     "type": "boolean"
                 },
                 "loc": {
@@ -350,95 +2799,218 @@ const docTemplate = `{
                         "type": "number"
                     }
                 },
-                "reason": {
+                "resolved_at": {
+                    "type": "string"
+                },
+                "resolved_by": {
                     "type": "string"
                 },
+                "similarity": {
+                    "type": "number"
+                },
                 "timestamp": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
+                    "type": "string"
                 }
             }
         },
-        "feeds.UpdateFeedLocationsRequest": {
+        "needs.DuplicatesResponse": {
             "type": "object",
             "properties": {
-                "feed_locations": {
+                "confirm_required": {
+                    "type": "boolean"
+                },
+                "duplicates": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/feeds.FeedLocation"
+                        "$ref": "#/definitions/needs.Duplicate"
                     }
                 }
             }
         },
-        "handler.RawFeed": {
+        "needs.HistoryResponse": {
             "type": "object",
             "properties": {
-                "channel": {
-                    "type": "string"
+                "count": {
+                    "type": "integer"
                 },
-                "epoch": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Change"
+                    }
+                }
+            }
+        },
+        "needs.LiteNeed": {
+            "type": "object",
+            "properties": {
+                "id": {
                     "type": "integer"
+                }
+            }
+        },
+        "needs.MergeNeedsRequest": {
+            "type": "object",
+            "properties": {
+                "duplicate_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "needs.Need": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
                 },
                 "extra_parameters": {
                     "type": "string"
                 },
+                "formatted_address": {
+                    "type": "string"
+                },
+                "geocode_status": {
+                    "type": "string"
+                },
                 "id": {
+                    "type": "integer"
+                },
+                "is_resolved": {
+                    "type": "boolean"
+                },
+                "loc": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "resolved_at": {
                     "type": "string"
                 },
-                "raw_text": {
+                "resolved_by": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
                     "type": "string"
                 }
             }
         },
-        "handler.request": {
+        "needs.Response": {
             "type": "object",
             "properties": {
-                "feeds": {
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.RawFeed"
+                        "$ref": "#/definitions/needs.Need"
                     }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "needs.CreateNeedRequest": {
+        "needs.UpdateNeedRequest": {
             "type": "object",
-            "required": [
-                "address",
-                "description"
-            ],
             "properties": {
                 "address": {
                     "type": "string"
                 },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
+                },
                 "description": {
                     "type": "string"
+                },
+                "extra_parameters": {
+                    "type": "object",
+                    "additionalProperties": true
                 }
             }
         },
-        "needs.LiteNeed": {
+        "offers.CreateOfferRequest": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
                 }
             }
         },
-        "needs.Need": {
+        "offers.Offer": {
             "type": "object",
             "properties": {
-                "description": {
+                "address": {
                     "type": "string"
                 },
-                "extra_parameters": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
+                    }
+                },
+                "created_at": {
                     "type": "string"
                 },
-                "formatted_address": {
+                "created_by": {
+                    "type": "string"
+                },
+                "description": {
                     "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "is_resolved": {
+                "is_active": {
                     "type": "boolean"
                 },
                 "loc": {
@@ -447,22 +3019,54 @@ const docTemplate = `{
                         "type": "number"
                     }
                 },
-                "timestamp": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "updated_by": {
                     "type": "string"
                 }
             }
         },
-        "needs.Response": {
+        "offers.Response": {
             "type": "object",
             "properties": {
                 "count": {
                     "type": "integer"
                 },
+                "next_cursor": {
+                    "type": "string"
+                },
                 "results": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/needs.Need"
+                        "$ref": "#/definitions/offers.Offer"
+                    }
+                }
+            }
+        },
+        "offers.UpdateOfferRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/needs.Category"
                     }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "latitude": {
+                    "type": "number"
+                },
+                "longitude": {
+                    "type": "number"
                 }
             }
         }